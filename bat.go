@@ -0,0 +1,98 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	BatHeaderSize  = transportHeaderSize
+	BatMaximumSize = transportMaximumSize
+	BatItemSize    = transportItemSize
+
+	// transport_stream_loop_length field size
+	batLoopSize = transportLoopSize
+)
+
+// BAT is Bouquet Association Table.
+// Has the same layout as NIT with bouquet descriptors instead of network descriptors.
+type BAT struct {
+	transportTable
+	Items []*BatItem
+}
+
+// BAT Item contains information about transport stream
+type BatItem struct {
+	transportItem
+}
+
+var (
+	ErrBatFormat = errors.New("bat: invalid format")
+)
+
+var (
+	emptyBat = []byte{
+		0x4A,        // table_id
+		0xF0,        // section_length 1
+		0x00,        // section_length 2
+		0x00,        // bouquet_id 1
+		0x00,        // bouquet_id 2
+		0xC0 | 0x01, // version
+		0x00,        // section_number
+		0x00,        // last_section_number
+		0xF0,        // bouquet_descriptors_length 1
+		0x00,        // bouquet_descriptors_length 2
+	}
+)
+
+func NewBat() *BAT {
+	t := new(BAT)
+	t.header = make([]byte, len(emptyBat))
+	copy(t.header, emptyBat)
+
+	return t
+}
+
+func (t *BAT) ParseBatSection(b []byte) error {
+	return t.parse(b, ErrBatFormat, "bat", func(item transportItem) {
+		t.Items = append(t.Items, &BatItem{item})
+	})
+}
+
+func (t *BAT) BouquetID() uint16 {
+	return binary.BigEndian.Uint16(t.header[3:])
+}
+
+func (t *BAT) SetBouquetID(id uint16) {
+	binary.BigEndian.PutUint16(t.header[3:], id)
+}
+
+// Calculates LastSectionNumber
+func (t *BAT) Finalize() {
+	t.finalize(t.itemHeader)
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from BAT
+func (t *BAT) Packetizer() *PsiPacketizer {
+	return newPsiPacketizer(t)
+}
+
+func (t *BAT) itemHeader(i int) []byte {
+	if i < len(t.Items) {
+		return t.Items[i].header
+	}
+
+	return nil
+}
+
+func (t *BAT) sectionSize(i int) int {
+	return t.size(i, t.itemHeader)
+}
+
+func (t *BAT) sectionItem(i int) []byte {
+	return t.item(i, t.itemHeader)
+}
+
+func NewBatItem() *BatItem {
+	return &BatItem{newTransportItem()}
+}
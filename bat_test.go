@@ -0,0 +1,115 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestBat(id uint16, name string, count int) *BAT {
+	bat := NewBat()
+	bat.SetVersion(1)
+	bat.SetBouquetID(id)
+	bat.AppendDescriptors((&Desc_47{BouquetName: name}).Encode())
+
+	for i := 0; i < count; i++ {
+		item := NewBatItem()
+		item.SetTSID(uint16(i + 1))
+		item.SetONID(1)
+		item.AppendDescriptors((&Desc_41{
+			Services: []Desc_41_Service{
+				{ServiceID: uint16(100 + i), ServiceType: 0x01},
+				{ServiceID: uint16(200 + i), ServiceType: 0x01},
+			},
+		}).Encode())
+		bat.Items = append(bat.Items, item)
+	}
+
+	bat.Finalize()
+
+	return bat
+}
+
+func TestBAT_Packetize(t *testing.T) {
+	assert := assert.New(t)
+
+	bouquets := []*BAT{
+		makeTestBat(0x1001, "Basic", 2),
+		makeTestBat(0x1002, "Premium", 100),
+	}
+
+	psi := new(PSI)
+	decoded := make(map[uint16]*BAT)
+	sections := 0
+
+	ts := NewTS(17)
+	for _, bat := range bouquets {
+		for p := bat.Packetizer(); p.Next(ts); ts.IncrementCC() {
+			psi.Assemble(ts, func(err error) {
+				if !assert.NoError(err) {
+					return
+				}
+
+				assert.Equal(uint8(0x4A), psi.TableID)
+
+				// sub-tables are distinguished by bouquet_id
+				bat, ok := decoded[psi.TableIDExtension]
+				if !ok {
+					bat = new(BAT)
+					decoded[psi.TableIDExtension] = bat
+				}
+
+				assert.NoError(bat.ParseBatSection(psi.Payload()))
+				sections += 1
+			})
+		}
+	}
+
+	assert.Equal(3, sections)
+	if !assert.Equal(2, len(decoded)) {
+		return
+	}
+
+	for _, expected := range bouquets {
+		bat := decoded[expected.BouquetID()]
+		if !assert.NotNil(bat) {
+			continue
+		}
+
+		assert.Equal(expected.BouquetID(), bat.BouquetID())
+		assert.Equal(uint8(1), bat.Version())
+		assert.Equal(expected.Descriptors(), bat.Descriptors())
+		assert.Equal(len(expected.Items), len(bat.Items))
+	}
+
+	name := new(Desc_47)
+	assert.NoError(name.Decode(decoded[0x1002].Descriptors()))
+	assert.Equal("Premium", name.BouquetName)
+
+	item := decoded[0x1002].Items[99]
+	assert.Equal(uint16(100), item.TSID())
+	assert.Equal(uint16(1), item.ONID())
+
+	services := new(Desc_41)
+	assert.NoError(services.Decode(item.Descriptors()))
+	assert.Equal(uint16(299), services.Services[1].ServiceID)
+}
+
+func TestBAT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	bat := makeTestBat(0x1001, "Basic", 1)
+	name := (&Desc_47{BouquetName: "Extended"}).Encode()
+	assert.NoError(bat.SetDescriptors(bat.Descriptors().Replace(name)))
+	assert.Equal(name, bat.Descriptors())
+
+	item := bat.Items[0]
+	assert.NoError(item.SetDescriptors(nil))
+	assert.Empty(item.Descriptors())
+	assert.Equal(uint16(1), item.TSID())
+
+	large := make(Descriptors, BatMaximumSize)
+	assert.ErrorIs(bat.AppendDescriptors(large), ErrDescriptorSize)
+	assert.ErrorIs(item.AppendDescriptors(large), ErrDescriptorSize)
+	assert.Error(item.SetDescriptors(Descriptors{0x41, 0x10}))
+}
@@ -0,0 +1,29 @@
+package mpegts
+
+// bcdDecode converts binary coded decimal value to the binary
+func bcdDecode(v uint32) uint32 {
+	r := uint32(0)
+	m := uint32(1)
+
+	for v != 0 {
+		r += (v & 0x0F) * m
+		m *= 10
+		v >>= 4
+	}
+
+	return r
+}
+
+// bcdEncode converts binary value to the binary coded decimal
+func bcdEncode(v uint32) uint32 {
+	r := uint32(0)
+	shift := 0
+
+	for v != 0 {
+		r |= (v % 10) << shift
+		shift += 4
+		v /= 10
+	}
+
+	return r
+}
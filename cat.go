@@ -0,0 +1,190 @@
+package mpegts
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+const (
+	CatHeaderSize  = 8
+	CatMaximumSize = 1024
+)
+
+// CAT is Conditional Access Table
+type CAT struct {
+	header []byte
+	desc   Descriptors
+
+	items []Descriptors // descriptors split for packetizer
+}
+
+var (
+	ErrCatFormat = errors.New("cat: invalid format")
+)
+
+var (
+	emptyCat = []byte{
+		0x01,        // table_id
+		0x80 | 0x30, // section_length 1
+		0x00,        // section_length 2
+		0xFF,        // reserved
+		0xFF,        // reserved
+		0xC0 | 0x01, // version
+		0x00,        // section_number
+		0x00,        // last_section_number
+	}
+)
+
+func NewCat() *CAT {
+	c := new(CAT)
+	c.header = make([]byte, len(emptyCat))
+	copy(c.header, emptyCat)
+
+	return c
+}
+
+// ParseCatSection parses CAT section.
+// Descriptors from each section appended to the CAT descriptors.
+func (c *CAT) ParseCatSection(b []byte) error {
+	if len(b) < (CatHeaderSize + crc32.Size) {
+		return ErrCatFormat
+	}
+
+	end := len(b) - crc32.Size
+
+	desc := Descriptors(b[CatHeaderSize:end])
+	if err := desc.Check(); err != nil {
+		return fmt.Errorf("cat: %w", err)
+	}
+
+	// copy header only from first section
+	if b[6] == 0 {
+		c.header = make([]byte, CatHeaderSize)
+		copy(c.header, b)
+	}
+
+	c.desc = append(c.desc, desc...)
+
+	return nil
+}
+
+func (c *CAT) Version() uint8 {
+	return (c.header[5] & 0x3E) >> 1
+}
+
+func (c *CAT) SetVersion(version uint8) {
+	c.header[5] &^= 0x3E
+	c.header[5] |= (version << 1) & 0x3E
+}
+
+func (c *CAT) Descriptors() Descriptors {
+	return c.desc
+}
+
+// AppendDescriptors appends descriptors to the CA descriptors.
+// CAT could be split into several sections, so size is not limited.
+// Returns ErrDescriptorFormat if desc has invalid descriptor loop.
+func (c *CAT) AppendDescriptors(desc Descriptors) error {
+	if len(desc) == 0 {
+		return nil
+	}
+
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	c.desc = append(c.desc, desc...)
+	return nil
+}
+
+// SetDescriptors replaces CA descriptors
+func (c *CAT) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	c.desc = append(Descriptors(nil), desc...)
+	return nil
+}
+
+// split returns list of descriptors
+func (c *CAT) split() []Descriptors {
+	var items []Descriptors
+
+	for d := c.desc; len(d) != 0; d = d.Next() {
+		items = append(items, d[:2+int(d[1])])
+	}
+
+	return items
+}
+
+// Calculates LastSectionNumber
+func (c *CAT) Finalize() {
+	c.header[6] = 0
+	c.header[7] = 0
+
+	remain := CatMaximumSize - CatHeaderSize - crc32.Size
+
+	for _, item := range c.split() {
+		is := len(item)
+		if is > remain {
+			remain = CatMaximumSize - CatHeaderSize - crc32.Size
+			c.header[7] += 1
+		}
+		remain -= is
+	}
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from CAT
+func (c *CAT) Packetizer() *PsiPacketizer {
+	c.items = c.split()
+	return newPsiPacketizer(c)
+}
+
+func (c *CAT) sectionSize(i int) int {
+	if i == len(c.items) {
+		return 0
+	}
+
+	if i == -1 {
+		i = 0
+	}
+
+	size := CatHeaderSize + crc32.Size
+
+	for i < len(c.items) {
+		is := len(c.items[i])
+		if (size + is) > CatMaximumSize {
+			break
+		} else {
+			size += is
+			i += 1
+		}
+	}
+
+	return size
+}
+
+func (c *CAT) sectionHeader(i int) []byte {
+	if i == -1 {
+		c.header[6] = 0
+	} else {
+		c.header[6] += 1
+	}
+
+	return c.header[:CatHeaderSize]
+}
+
+func (c *CAT) sectionItem(i int) []byte {
+	if i == -1 {
+		return []byte{}
+	}
+
+	if i < len(c.items) {
+		return c.items[i]
+	}
+
+	return nil
+}
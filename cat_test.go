@@ -0,0 +1,133 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var catTestData = []byte{
+	0x01, 0xB0, 0x17, 0xFF, 0xFF, 0xC5, 0x00, 0x00,
+	0x09, 0x04, 0x06, 0x04, 0xE1, 0x00, 0x09, 0x06,
+	0x05, 0x00, 0xE1, 0x01, 0xAA, 0xBB, 0xC3, 0x76,
+	0x5B, 0x8E,
+}
+
+func TestCAT_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	cat := new(CAT)
+	if err := cat.ParseCatSection(catTestData); !assert.NoError(err) {
+		return
+	}
+
+	assert.Equal(uint8(2), cat.Version())
+	assert.Equal(14, len(cat.Descriptors()))
+
+	list := cat.Descriptors().CADescriptors()
+	if assert.Equal(2, len(list)) {
+		assert.Equal(uint16(0x0604), list[0].CASystemID)
+		assert.Equal(PID(256), list[0].CAPID)
+		assert.Nil(list[0].PrivateData)
+
+		assert.Equal(uint16(0x0500), list[1].CASystemID)
+		assert.Equal(PID(257), list[1].CAPID)
+		assert.Equal([]byte{0xAA, 0xBB}, list[1].PrivateData)
+	}
+
+	// invalid descriptors
+	data := make([]byte, len(catTestData))
+	copy(data, catTestData)
+	data[9] = 0x20
+	assert.ErrorIs(new(CAT).ParseCatSection(data), ErrDescriptorFormat)
+}
+
+func TestCAT_Packetize(t *testing.T) {
+	t.Run("normal", func(t *testing.T) {
+		assert := assert.New(t)
+
+		cat := NewCat()
+		cat.SetVersion(2)
+		cat.AppendDescriptors((&Desc_09{
+			CASystemID: 0x0604,
+			CAPID:      256,
+		}).Encode())
+		cat.AppendDescriptors((&Desc_09{
+			CASystemID:  0x0500,
+			CAPID:       257,
+			PrivateData: []byte{0xAA, 0xBB},
+		}).Encode())
+		cat.Finalize()
+
+		counter := 0
+		ts := NewTS(1)
+		ts.SetCC(1)
+		for p := cat.Packetizer(); p.Next(ts); ts.IncrementCC() {
+			assert.Equal(catTestData, []byte(ts[5:5+len(catTestData)]))
+			counter += 1
+		}
+		assert.Equal(1, counter)
+	})
+
+	t.Run("multi-section", func(t *testing.T) {
+		assert := assert.New(t)
+
+		cat := NewCat()
+		for i := 0; i < 200; i++ {
+			cat.AppendDescriptors((&Desc_09{
+				CASystemID: 0x0100,
+				CAPID:      PID(1000 + i),
+			}).Encode())
+		}
+		cat.Finalize()
+
+		psi := new(PSI)
+		decoded := new(CAT)
+		sections := 0
+
+		ts := NewTS(1)
+		for p := cat.Packetizer(); p.Next(ts); ts.IncrementCC() {
+			psi.Assemble(ts, func(err error) {
+				if !assert.NoError(err) {
+					return
+				}
+				assert.Equal(uint8(1), psi.LastSectionNumber)
+				assert.NoError(decoded.ParseCatSection(psi.Payload()))
+				sections += 1
+			})
+		}
+
+		assert.Equal(2, sections)
+		assert.Equal(cat.Descriptors(), decoded.Descriptors())
+		assert.Equal(200, len(decoded.Descriptors().CADescriptors()))
+	})
+}
+
+func TestCAT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	cat := NewCat()
+	assert.NoError(cat.AppendDescriptors(Descriptors{0x09, 0x04, 0x06, 0x04, 0xE1, 0x00}))
+	assert.ErrorIs(cat.AppendDescriptors(Descriptors{0x09, 0x04, 0x06}), ErrDescriptorFormat)
+	assert.Equal(Descriptors{0x09, 0x04, 0x06, 0x04, 0xE1, 0x00}, cat.Descriptors())
+
+	desc := Descriptors{0x09, 0x04, 0x05, 0x00, 0xE1, 0x01}
+	assert.NoError(cat.SetDescriptors(desc))
+	assert.Equal(desc, cat.Descriptors())
+	assert.ErrorIs(cat.SetDescriptors(Descriptors{0x09}), ErrDescriptorFormat)
+}
+
+func TestDesc_09_PrivateDataLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	d := &Desc_09{CASystemID: 0x0B00, CAPID: 100, PrivateData: make([]byte, 300)}
+	desc := d.Encode()
+	assert.Equal(byte(0xFF), desc[1])
+	assert.Equal(2+0xFF, len(desc))
+
+	result := new(Desc_09)
+	if assert.NoError(result.Decode(desc)) {
+		assert.Equal(0xFF-4, len(result.PrivateData))
+		assert.Equal(PID(100), result.CAPID)
+	}
+}
@@ -0,0 +1,246 @@
+package mpegts
+
+import (
+	"encoding/binary"
+)
+
+// PacketFn is a callback to process TS packet
+type PacketFn func(packet TS)
+
+// ProgramEvent is a program state change in the Demuxer
+type ProgramEvent int
+
+const (
+	ProgramAdded ProgramEvent = iota
+	ProgramChanged
+	ProgramRemoved
+)
+
+// ProgramFn is a Demuxer callback on program state change.
+// For ProgramRemoved event pmt is the last received PMT or nil if PMT was not received.
+type ProgramFn func(event ProgramEvent, pnr uint16, pmt *PMT)
+
+// Demuxer dispatches TS packets to handlers by PID.
+// Demuxer follows PAT and PMT to track programs in the stream.
+type Demuxer struct {
+	handlers  map[PID]PacketFn
+	fallback  PacketFn
+	onProgram ProgramFn
+
+	psi map[PID]*PSI
+
+	pat      *PAT // last complete PAT
+	patBuild *PAT // PAT sections in progress
+
+	programs map[uint16]*demuxProgram
+}
+
+type demuxProgram struct {
+	pid   PID
+	pmt   *PMT // last complete PMT
+	build *PMT // PMT sections in progress
+}
+
+func NewDemuxer() *Demuxer {
+	d := &Demuxer{
+		handlers: make(map[PID]PacketFn),
+		psi:      make(map[PID]*PSI),
+		programs: make(map[uint16]*demuxProgram),
+	}
+
+	d.psi[0] = new(PSI)
+
+	return d
+}
+
+// SetHandler registers packet handler for PID.
+// If fn is nil handler will be removed.
+func (d *Demuxer) SetHandler(pid PID, fn PacketFn) {
+	if fn == nil {
+		delete(d.handlers, pid)
+	} else {
+		d.handlers[pid] = fn
+	}
+}
+
+// RemoveHandler removes packet handler for PID
+func (d *Demuxer) RemoveHandler(pid PID) {
+	delete(d.handlers, pid)
+}
+
+// SetDefaultHandler registers packet handler for all PIDs without own handler
+func (d *Demuxer) SetDefaultHandler(fn PacketFn) {
+	d.fallback = fn
+}
+
+// SetProgramHandler registers callback for program state changes
+func (d *Demuxer) SetProgramHandler(fn ProgramFn) {
+	d.onProgram = fn
+}
+
+// PAT returns last complete PAT or nil
+func (d *Demuxer) PAT() *PAT {
+	return d.pat
+}
+
+// PMT returns last complete PMT for program or nil
+func (d *Demuxer) PMT(pnr uint16) *PMT {
+	if program, ok := d.programs[pnr]; ok {
+		return program.pmt
+	}
+
+	return nil
+}
+
+// Demux processes TS packet
+func (d *Demuxer) Demux(packet TS) {
+	pid := packet.PID()
+
+	if psi, ok := d.psi[pid]; ok {
+		psi.Assemble(packet, func(err error) {
+			if err != nil {
+				return
+			}
+
+			if pid == 0 {
+				d.onPat(psi)
+			} else {
+				d.onPmt(psi)
+			}
+		})
+	}
+
+	if fn, ok := d.handlers[pid]; ok {
+		fn(packet)
+	} else if d.fallback != nil {
+		d.fallback(packet)
+	}
+}
+
+func (d *Demuxer) programEvent(event ProgramEvent, pnr uint16, pmt *PMT) {
+	if d.onProgram != nil {
+		d.onProgram(event, pnr, pmt)
+	}
+}
+
+// isCurrentSection checks current_next_indicator
+func isCurrentSection(b []byte) bool {
+	return (b[5] & 0x01) != 0
+}
+
+func (d *Demuxer) onPat(psi *PSI) {
+	b := psi.Payload()
+	if psi.TableID != 0x00 || !isCurrentSection(b) {
+		return
+	}
+
+	if psi.SectionNumber == 0 {
+		if d.pat != nil && d.pat.Version() == psi.Version {
+			d.patBuild = nil
+			return
+		}
+
+		d.patBuild = new(PAT)
+	} else if d.patBuild == nil ||
+		d.patBuild.Version() != psi.Version ||
+		d.patBuild.header[7] != psi.LastSectionNumber {
+		d.patBuild = nil
+		return
+	}
+
+	if err := d.patBuild.ParsePatSection(b); err != nil {
+		d.patBuild = nil
+		return
+	}
+
+	if psi.SectionNumber == psi.LastSectionNumber {
+		pat := d.patBuild
+		d.patBuild = nil
+		d.updatePat(pat)
+	}
+}
+
+func (d *Demuxer) updatePat(pat *PAT) {
+	d.pat = pat
+
+	actual := make(map[uint16]PID)
+	for _, item := range pat.Items {
+		if pnr := item.PNR(); pnr != 0 {
+			actual[pnr] = item.PID()
+		}
+	}
+
+	for pnr, program := range d.programs {
+		if pid, ok := actual[pnr]; !ok || pid != program.pid {
+			delete(d.programs, pnr)
+			d.programEvent(ProgramRemoved, pnr, program.pmt)
+		}
+	}
+
+	// drop assemblers for unused PMT PIDs
+	used := make(map[PID]bool)
+	for _, pid := range actual {
+		used[pid] = true
+	}
+	for pid := range d.psi {
+		if pid != 0 && !used[pid] {
+			delete(d.psi, pid)
+		}
+	}
+
+	for pnr, pid := range actual {
+		if _, ok := d.programs[pnr]; ok {
+			continue
+		}
+
+		d.programs[pnr] = &demuxProgram{pid: pid}
+		if _, ok := d.psi[pid]; !ok {
+			d.psi[pid] = new(PSI)
+		}
+	}
+}
+
+func (d *Demuxer) onPmt(psi *PSI) {
+	b := psi.Payload()
+	if psi.TableID != 0x02 || !isCurrentSection(b) {
+		return
+	}
+
+	pnr := binary.BigEndian.Uint16(b[3:])
+	program, ok := d.programs[pnr]
+	if !ok {
+		return
+	}
+
+	if psi.SectionNumber == 0 {
+		if program.pmt != nil && program.pmt.Version() == psi.Version {
+			program.build = nil
+			return
+		}
+
+		program.build = new(PMT)
+	} else if program.build == nil ||
+		program.build.Version() != psi.Version ||
+		program.build.header[7] != psi.LastSectionNumber {
+		program.build = nil
+		return
+	}
+
+	if err := program.build.ParsePmtSection(b); err != nil {
+		program.build = nil
+		return
+	}
+
+	if psi.SectionNumber == psi.LastSectionNumber {
+		pmt := program.build
+		program.build = nil
+
+		event := ProgramChanged
+		if program.pmt == nil {
+			event = ProgramAdded
+		}
+
+		program.pmt = pmt
+		d.programEvent(event, pnr, pmt)
+	}
+}
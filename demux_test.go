@@ -0,0 +1,146 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type demuxEvent struct {
+	event ProgramEvent
+	pnr   uint16
+}
+
+func makeDemuxPat(version uint8, programs map[uint16]PID) []TS {
+	pat := NewPat()
+	pat.SetVersion(version)
+	pat.SetTSID(1)
+
+	for pnr := uint16(1); pnr < 10; pnr++ {
+		if pid, ok := programs[pnr]; ok {
+			item := NewPatItem()
+			item.SetPNR(pnr)
+			item.SetPID(pid)
+			pat.Items = append(pat.Items, item)
+		}
+	}
+
+	pat.Finalize()
+
+	var result []TS
+	ts := NewTS(0)
+	for p := pat.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		packet := NewTS(0)
+		copy(packet, ts)
+		result = append(result, packet)
+	}
+
+	return result
+}
+
+func makeDemuxPmt(pid PID, pnr uint16, version uint8) []TS {
+	pmt := NewPmt()
+	pmt.SetVersion(version)
+	pmt.SetPNR(pnr)
+	pmt.SetPCR(pid + 1)
+
+	item := NewPmtItem()
+	item.SetType(0x1B)
+	item.SetPID(pid + 1)
+	pmt.Items = append(pmt.Items, item)
+
+	pmt.Finalize()
+
+	var result []TS
+	ts := NewTS(pid)
+	for p := pmt.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		packet := NewTS(pid)
+		copy(packet, ts)
+		result = append(result, packet)
+	}
+
+	return result
+}
+
+func TestDemuxer_Programs(t *testing.T) {
+	assert := assert.New(t)
+
+	var events []demuxEvent
+
+	demux := NewDemuxer()
+	demux.SetProgramHandler(func(event ProgramEvent, pnr uint16, pmt *PMT) {
+		events = append(events, demuxEvent{event, pnr})
+		if event != ProgramRemoved {
+			assert.Equal(pnr, pmt.PNR())
+		}
+	})
+
+	demuxAll := func(list ...[]TS) {
+		for _, packets := range list {
+			for _, packet := range packets {
+				demux.Demux(packet)
+			}
+		}
+	}
+
+	// PMT before PAT is ignored
+	demuxAll(makeDemuxPmt(100, 1, 0))
+	assert.Empty(events)
+
+	demuxAll(
+		makeDemuxPat(0, map[uint16]PID{1: 100, 2: 200}),
+		makeDemuxPmt(100, 1, 0),
+		makeDemuxPmt(200, 2, 0),
+	)
+	assert.Equal([]demuxEvent{{ProgramAdded, 1}, {ProgramAdded, 2}}, events)
+	assert.Equal(2, len(demux.PAT().Items))
+	assert.Equal(PID(101), demux.PMT(1).PCR())
+
+	// repeat of the same version
+	events = nil
+	demuxAll(
+		makeDemuxPat(0, map[uint16]PID{1: 100, 2: 200}),
+		makeDemuxPmt(100, 1, 0),
+		makeDemuxPmt(200, 2, 0),
+	)
+	assert.Empty(events)
+	assert.Equal(2, len(demux.PAT().Items))
+
+	// version change
+	events = nil
+	demuxAll(makeDemuxPmt(200, 2, 1))
+	assert.Equal([]demuxEvent{{ProgramChanged, 2}}, events)
+
+	// program removed
+	events = nil
+	demuxAll(makeDemuxPat(1, map[uint16]PID{2: 200}))
+	assert.Equal([]demuxEvent{{ProgramRemoved, 1}}, events)
+	assert.Nil(demux.PMT(1))
+	assert.NotNil(demux.PMT(2))
+}
+
+func TestDemuxer_Handlers(t *testing.T) {
+	assert := assert.New(t)
+
+	demux := NewDemuxer()
+
+	var pid100, other int
+	demux.SetHandler(100, func(packet TS) {
+		assert.Equal(PID(100), packet.PID())
+		pid100 += 1
+	})
+	demux.SetDefaultHandler(func(packet TS) {
+		other += 1
+	})
+
+	demux.Demux(NewTS(100))
+	demux.Demux(NewTS(101))
+	demux.Demux(NullTS)
+	assert.Equal(1, pid100)
+	assert.Equal(2, other)
+
+	demux.RemoveHandler(100)
+	demux.Demux(NewTS(100))
+	assert.Equal(1, pid100)
+	assert.Equal(3, other)
+}
@@ -0,0 +1,81 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_09 CA_descriptor.
+// In the CAT defines EMM PID, in the PMT defines ECM PID.
+// Encode truncates PrivateData to fit into the descriptor.
+type Desc_09 struct {
+	CASystemID  uint16
+	CAPID       PID
+	PrivateData []byte
+}
+
+func (d *Desc_09) String() string {
+	return fmt.Sprintf("0x09 CA_descriptor: CA_system_id=0x%04X, CA_PID=%d", d.CASystemID, d.CAPID)
+}
+
+func (d *Desc_09) Tag() uint8 {
+	return 0x09
+}
+
+func (d *Desc_09) Encode() (desc Descriptors) {
+	// CA_system_id: 16bit
+	// reserved: 3bit, CA_PID: 13bit
+	data := d.PrivateData
+	if len(data) > 0xFF-4 {
+		data = data[:0xFF-4]
+	}
+
+	descLen := 4 + len(data)
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x09
+	desc[1] = byte(descLen)
+	binary.BigEndian.PutUint16(desc[2:], d.CASystemID)
+	desc[4] = 0xE0
+	setPID(desc[4:], d.CAPID)
+	copy(desc[6:], data)
+	return desc
+}
+
+func (d *Desc_09) Decode(desc Descriptors) error {
+	if len(desc) < 6 || desc[0] != 0x09 || desc[1] < 4 || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	d.CASystemID = binary.BigEndian.Uint16(desc[2:])
+	d.CAPID = getPID(desc[4:])
+	d.PrivateData = nil
+	if desc[1] > 4 {
+		d.PrivateData = make([]byte, desc[1]-4)
+		copy(d.PrivateData, desc[6:])
+	}
+
+	return nil
+}
+
+// CADescriptors returns all valid CA descriptors from the descriptors loop.
+// Used to get EMM PIDs from CAT or ECM PIDs from PMT.
+func (d Descriptors) CADescriptors() []*Desc_09 {
+	var list []*Desc_09
+
+	if d.Check() != nil {
+		return nil
+	}
+
+	for ; len(d) != 0; d = d.Next() {
+		if d[0] != 0x09 {
+			continue
+		}
+
+		ca := new(Desc_09)
+		if err := ca.Decode(d); err == nil {
+			list = append(list, ca)
+		}
+	}
+
+	return list
+}
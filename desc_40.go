@@ -0,0 +1,40 @@
+package mpegts
+
+import (
+	"fmt"
+
+	"github.com/cesbo/go-mpegts/textcode"
+)
+
+// Desc_40 network_name_descriptor.
+// NetworkName is DVB text with character table selector, see textcode.DecodeDVB.
+// Encode truncates name to fit into the descriptor.
+type Desc_40 struct {
+	NetworkName string
+}
+
+func (d *Desc_40) String() string {
+	return fmt.Sprintf("0x40 network_name_descriptor: network_name=%s", d.NetworkName)
+}
+
+func (d *Desc_40) Tag() uint8 {
+	return 0x40
+}
+
+func (d *Desc_40) Encode() (desc Descriptors) {
+	name := encodeText(d.NetworkName, 0xFF)
+	desc = make(Descriptors, len(name)+2)
+	desc[0] = 0x40
+	desc[1] = byte(len(name))
+	copy(desc[2:], name)
+	return desc
+}
+
+func (d *Desc_40) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x40 || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	d.NetworkName = textcode.DecodeDVB(desc[2 : 2+int(desc[1])])
+	return nil
+}
@@ -0,0 +1,54 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_41 service_list_descriptor
+type Desc_41 struct {
+	Services []Desc_41_Service
+}
+
+type Desc_41_Service struct {
+	ServiceID   uint16
+	ServiceType uint8
+}
+
+func (d *Desc_41) String() string {
+	return fmt.Sprintf("0x41 service_list_descriptor: services=%d", len(d.Services))
+}
+
+func (d *Desc_41) Tag() uint8 {
+	return 0x41
+}
+
+func (d *Desc_41) Encode() (desc Descriptors) {
+	// service_id: 16bit
+	// service_type: 8bit
+	descLen := len(d.Services) * 3
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x41
+	desc[1] = byte(descLen)
+	for i, s := range d.Services {
+		binary.BigEndian.PutUint16(desc[2+i*3:], s.ServiceID)
+		desc[4+i*3] = s.ServiceType
+	}
+	return desc
+}
+
+func (d *Desc_41) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x41 || len(desc) < 2+int(desc[1]) || desc[1]%3 != 0 {
+		return ErrDescriptorFormat
+	}
+
+	d.Services = nil
+	for skip := 2; skip < 2+int(desc[1]); skip += 3 {
+		d.Services = append(d.Services, Desc_41_Service{
+			ServiceID:   binary.BigEndian.Uint16(desc[skip:]),
+			ServiceType: desc[skip+2],
+		})
+	}
+
+	return nil
+}
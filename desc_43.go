@@ -0,0 +1,67 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_43 satellite_delivery_system_descriptor
+type Desc_43 struct {
+	Frequency       uint32 // in 10 kHz units
+	OrbitalPosition uint16 // in 0.1 degree units
+	East            bool   // west_east_flag
+	Polarization    uint8  // 0 - linear horizontal, 1 - linear vertical, 2 - circular left, 3 - circular right
+	RollOff         uint8  // 0 - 0.35, 1 - 0.25, 2 - 0.20. Only for DVB-S2
+	S2              bool   // modulation_system
+	ModulationType  uint8  // 0 - auto, 1 - QPSK, 2 - 8PSK, 3 - 16-QAM
+	SymbolRate      uint32 // in 100 symbol/s units
+	FECInner        uint8
+}
+
+func (d *Desc_43) String() string {
+	return fmt.Sprintf("0x43 satellite_delivery_system_descriptor: frequency=%d, symbol_rate=%d", d.Frequency, d.SymbolRate)
+}
+
+func (d *Desc_43) Tag() uint8 {
+	return 0x43
+}
+
+func (d *Desc_43) Encode() (desc Descriptors) {
+	desc = make(Descriptors, 13)
+	desc[0] = 0x43
+	desc[1] = 11
+	binary.BigEndian.PutUint32(desc[2:], bcdEncode(d.Frequency))
+	binary.BigEndian.PutUint16(desc[6:], uint16(bcdEncode(uint32(d.OrbitalPosition))))
+	desc[8] = (d.Polarization & 0x03) << 5
+	if d.East {
+		desc[8] |= 0x80
+	}
+	if d.S2 {
+		desc[8] |= 0x04 | ((d.RollOff & 0x03) << 3)
+	}
+	desc[8] |= d.ModulationType & 0x03
+	binary.BigEndian.PutUint32(desc[9:], (bcdEncode(d.SymbolRate)<<4)|uint32(d.FECInner&0x0F))
+	return desc
+}
+
+func (d *Desc_43) Decode(desc Descriptors) error {
+	if len(desc) < 13 || desc[0] != 0x43 || desc[1] < 11 {
+		return ErrDescriptorFormat
+	}
+
+	d.Frequency = bcdDecode(binary.BigEndian.Uint32(desc[2:]))
+	d.OrbitalPosition = uint16(bcdDecode(uint32(binary.BigEndian.Uint16(desc[6:]))))
+	d.East = (desc[8] & 0x80) != 0
+	d.Polarization = (desc[8] >> 5) & 0x03
+	d.S2 = (desc[8] & 0x04) != 0
+	d.RollOff = 0
+	if d.S2 {
+		d.RollOff = (desc[8] >> 3) & 0x03
+	}
+	d.ModulationType = desc[8] & 0x03
+	v := binary.BigEndian.Uint32(desc[9:])
+	d.SymbolRate = bcdDecode(v >> 4)
+	d.FECInner = uint8(v & 0x0F)
+
+	return nil
+}
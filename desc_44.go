@@ -0,0 +1,49 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_44 cable_delivery_system_descriptor
+type Desc_44 struct {
+	Frequency  uint32 // in 100 Hz units
+	FECOuter   uint8
+	Modulation uint8  // 1 - 16-QAM, 2 - 32-QAM, 3 - 64-QAM, 4 - 128-QAM, 5 - 256-QAM
+	SymbolRate uint32 // in 100 symbol/s units
+	FECInner   uint8
+}
+
+func (d *Desc_44) String() string {
+	return fmt.Sprintf("0x44 cable_delivery_system_descriptor: frequency=%d, symbol_rate=%d", d.Frequency, d.SymbolRate)
+}
+
+func (d *Desc_44) Tag() uint8 {
+	return 0x44
+}
+
+func (d *Desc_44) Encode() (desc Descriptors) {
+	desc = make(Descriptors, 13)
+	desc[0] = 0x44
+	desc[1] = 11
+	binary.BigEndian.PutUint32(desc[2:], bcdEncode(d.Frequency))
+	binary.BigEndian.PutUint16(desc[6:], 0xFFF0|uint16(d.FECOuter&0x0F))
+	desc[8] = d.Modulation
+	binary.BigEndian.PutUint32(desc[9:], (bcdEncode(d.SymbolRate)<<4)|uint32(d.FECInner&0x0F))
+	return desc
+}
+
+func (d *Desc_44) Decode(desc Descriptors) error {
+	if len(desc) < 13 || desc[0] != 0x44 || desc[1] < 11 {
+		return ErrDescriptorFormat
+	}
+
+	d.Frequency = bcdDecode(binary.BigEndian.Uint32(desc[2:]))
+	d.FECOuter = desc[7] & 0x0F
+	d.Modulation = desc[8]
+	v := binary.BigEndian.Uint32(desc[9:])
+	d.SymbolRate = bcdDecode(v >> 4)
+	d.FECInner = uint8(v & 0x0F)
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package mpegts
+
+import (
+	"fmt"
+
+	"github.com/cesbo/go-mpegts/textcode"
+)
+
+// Desc_47 bouquet_name_descriptor.
+// Encode truncates name to fit into the descriptor.
+type Desc_47 struct {
+	BouquetName string
+}
+
+func (d *Desc_47) String() string {
+	return fmt.Sprintf("0x47 bouquet_name_descriptor: bouquet_name=%s", d.BouquetName)
+}
+
+func (d *Desc_47) Tag() uint8 {
+	return 0x47
+}
+
+func (d *Desc_47) Encode() (desc Descriptors) {
+	name := encodeText(d.BouquetName, 0xFF)
+	desc = make(Descriptors, len(name)+2)
+	desc[0] = 0x47
+	desc[1] = byte(len(name))
+	copy(desc[2:], name)
+	return desc
+}
+
+func (d *Desc_47) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x47 || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	d.BouquetName = textcode.DecodeDVB(desc[2 : 2+int(desc[1])])
+	return nil
+}
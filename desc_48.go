@@ -2,16 +2,24 @@ package mpegts
 
 import (
 	"fmt"
-	"log"
+
+	"github.com/cesbo/go-mpegts/textcode"
 )
 
+// Desc_48 service_descriptor.
+// Encode truncates names to fit into the descriptor.
 type Desc_48 struct {
+	ServiceType         uint8 // 0x01 - digital television, 0x02 - digital radio, etc
 	ServiceProviderName string
 	ServiceName         string
 }
 
 func (d *Desc_48) String() string {
-	return fmt.Sprintf("0x48 service_descriptor: service_provider_name= %s, service_name: %s", d.ServiceProviderName, d.ServiceName)
+	return fmt.Sprintf("0x48 service_descriptor: service_type=0x%02X, service_provider_name= %s, service_name: %s", d.ServiceType, d.ServiceProviderName, d.ServiceName)
+}
+
+func (d *Desc_48) Tag() uint8 {
+	return 0x48
 }
 
 func (d *Desc_48) Encode() (desc Descriptors) {
@@ -21,25 +29,42 @@ func (d *Desc_48) Encode() (desc Descriptors) {
 	// service_type: 8bit
 	// service_provider_name_length: 8bit
 	// service_name_length: 8bit
-	desc_len := 3 + len(d.ServiceProviderName) + len(d.ServiceName) //3 is the service_type + service_provider_name_length + service_name_length
+	provider := encodeText(d.ServiceProviderName, 0xFF-3)
+	name := encodeText(d.ServiceName, 0xFF-3-len(provider))
+	desc_len := 3 + len(provider) + len(name) //3 is the service_type + service_provider_name_length + service_name_length
 	desc = make(Descriptors, desc_len+2)
 	desc[0] = 0x48
 	desc[1] = byte(desc_len)
-	desc[2] = 0x01
-	desc[3] = byte(len(d.ServiceProviderName))
-	copy(desc[4:], []byte(d.ServiceProviderName))
-	desc[4+len(d.ServiceProviderName)] = byte(len(d.ServiceName))
-	copy(desc[5+len(d.ServiceProviderName):], []byte(d.ServiceName))
+	desc[2] = d.ServiceType
+	desc[3] = byte(len(provider))
+	copy(desc[4:], provider)
+	desc[4+len(provider)] = byte(len(name))
+	copy(desc[5+len(provider):], name)
 	return desc
 }
 
 func (d *Desc_48) Decode(desc Descriptors) error {
-	if len(desc) >= 8 && desc[0] == 0x48 {
-		d.ServiceProviderName = string(desc[4 : 4+desc[3]])
-		d.ServiceName = string(desc[5+desc[3] : 5+desc[3]+desc[4+desc[3]]])
-		return nil
-	} else {
-		log.Printf("Desc_48: invalid format")
+	if len(desc) < 5 || desc[0] != 0x48 || len(desc) < 2+int(desc[1]) {
 		return ErrDescriptorFormat
 	}
+
+	end := 2 + int(desc[1])
+
+	skip := 4
+	next := skip + int(desc[3])
+	if next >= end {
+		return ErrDescriptorFormat
+	}
+	provider := desc[skip:next]
+
+	skip = next + 1
+	next = skip + int(desc[skip-1])
+	if next > end {
+		return ErrDescriptorFormat
+	}
+
+	d.ServiceType = desc[2]
+	d.ServiceProviderName = textcode.DecodeDVB(provider)
+	d.ServiceName = textcode.DecodeDVB(desc[skip:next])
+	return nil
 }
@@ -0,0 +1,99 @@
+package mpegts
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/cesbo/go-mpegts/textcode"
+)
+
+// Desc_4D short_event_descriptor.
+// EventName and Text are truncated on encoding to fit into the descriptor.
+type Desc_4D struct {
+	Language  string // ISO 639-2 language code
+	EventName string
+	Text      string
+}
+
+func (d *Desc_4D) String() string {
+	return fmt.Sprintf("0x4D short_event_descriptor: language=%s, event_name=%s", d.Language, d.EventName)
+}
+
+func (d *Desc_4D) Tag() uint8 {
+	return 0x4D
+}
+
+func (d *Desc_4D) Encode() (desc Descriptors) {
+	// ISO_639_language_code: 24bit
+	// event_name_length: 8bit
+	// text_length: 8bit
+	name := encodeText(d.EventName, 0xFF-5)
+	text := encodeText(d.Text, 0xFF-5-len(name))
+
+	descLen := 5 + len(name) + len(text)
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x4D
+	desc[1] = byte(descLen)
+	putLanguage(desc[2:], d.Language)
+	desc[5] = byte(len(name))
+	copy(desc[6:], name)
+	desc[6+len(name)] = byte(len(text))
+	copy(desc[7+len(name):], text)
+	return desc
+}
+
+func (d *Desc_4D) Decode(desc Descriptors) error {
+	if len(desc) < 7 || desc[0] != 0x4D || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	end := 2 + int(desc[1])
+
+	next := 6 + int(desc[5])
+	if next+1 > end {
+		return ErrDescriptorFormat
+	}
+	name := desc[6:next]
+
+	skip := next + 1
+	next = skip + int(desc[next])
+	if next > end {
+		return ErrDescriptorFormat
+	}
+
+	d.Language = string(desc[2:5])
+	d.EventName = textcode.DecodeDVB(name)
+	d.Text = textcode.DecodeDVB(desc[skip:next])
+
+	return nil
+}
+
+// putLanguage puts 3-character ISO 639-2 language code
+func putLanguage(b []byte, lang string) {
+	copy(b[:3], "   ")
+	copy(b[:3], lang)
+}
+
+// encodeText converts an UTF-8 string into DVB text.
+// Text is truncated by characters to fit into limit bytes.
+func encodeText(s string, limit int) []byte {
+	// each character takes at least one byte
+	n := 0
+	for i := range s {
+		if n == limit {
+			s = s[:i]
+			break
+		}
+		n += 1
+	}
+
+	for {
+		text := textcode.EncodeDVB(s)
+		if len(text) <= limit {
+			return text
+		}
+
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+}
@@ -0,0 +1,117 @@
+package mpegts
+
+import (
+	"fmt"
+
+	"github.com/cesbo/go-mpegts/textcode"
+)
+
+// Desc_4E extended_event_descriptor.
+// On encoding, items that do not fit into the descriptor are skipped
+// and Text is truncated. Long text should be split into several
+// descriptors with Number and LastNumber.
+type Desc_4E struct {
+	Number     uint8 // descriptor_number
+	LastNumber uint8 // last_descriptor_number
+	Language   string
+	Items      []Desc_4E_Item
+	Text       string
+}
+
+type Desc_4E_Item struct {
+	Description string
+	Item        string
+}
+
+func (d *Desc_4E) String() string {
+	return fmt.Sprintf("0x4E extended_event_descriptor: number=%d/%d, language=%s", d.Number, d.LastNumber, d.Language)
+}
+
+func (d *Desc_4E) Tag() uint8 {
+	return 0x4E
+}
+
+func (d *Desc_4E) Encode() (desc Descriptors) {
+	desc = make(Descriptors, 7)
+	desc[0] = 0x4E
+	desc[2] = (d.Number << 4) | (d.LastNumber & 0x0F)
+	putLanguage(desc[3:], d.Language)
+
+	// descriptor_number, ISO_639_language_code,
+	// length_of_items, and text_length
+	room := 0xFF - 6
+
+	for _, item := range d.Items {
+		description := textcode.EncodeDVB(item.Description)
+		value := textcode.EncodeDVB(item.Item)
+
+		size := 2 + len(description) + len(value)
+		if size > room {
+			break
+		}
+		room -= size
+
+		desc = append(desc, byte(len(description)))
+		desc = append(desc, description...)
+		desc = append(desc, byte(len(value)))
+		desc = append(desc, value...)
+	}
+	desc[6] = byte(len(desc) - 7)
+
+	text := encodeText(d.Text, room)
+	desc = append(desc, byte(len(text)))
+	desc = append(desc, text...)
+
+	desc[1] = byte(len(desc) - 2)
+	return desc
+}
+
+func (d *Desc_4E) Decode(desc Descriptors) error {
+	if len(desc) < 8 || desc[0] != 0x4E || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	end := 2 + int(desc[1])
+
+	itemsEnd := 7 + int(desc[6])
+	if itemsEnd+1 > end {
+		return ErrDescriptorFormat
+	}
+
+	var items []Desc_4E_Item
+
+	skip := 7
+	for skip < itemsEnd {
+		next := skip + 1 + int(desc[skip])
+		if next+1 > itemsEnd {
+			return ErrDescriptorFormat
+		}
+		description := desc[skip+1 : next]
+
+		skip = next
+		next = skip + 1 + int(desc[skip])
+		if next > itemsEnd {
+			return ErrDescriptorFormat
+		}
+
+		items = append(items, Desc_4E_Item{
+			Description: textcode.DecodeDVB(description),
+			Item:        textcode.DecodeDVB(desc[skip+1 : next]),
+		})
+
+		skip = next
+	}
+
+	next := itemsEnd + 1 + int(desc[itemsEnd])
+	if next > end {
+		return ErrDescriptorFormat
+	}
+
+	d.Number = desc[2] >> 4
+	d.LastNumber = desc[2] & 0x0F
+	d.Language = string(desc[3:6])
+	d.Items = items
+	d.Text = textcode.DecodeDVB(desc[itemsEnd+1 : next])
+
+	return nil
+}
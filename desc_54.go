@@ -0,0 +1,53 @@
+package mpegts
+
+import (
+	"fmt"
+)
+
+// Desc_54 content_descriptor
+type Desc_54 struct {
+	Items []Desc_54_Item
+}
+
+type Desc_54_Item struct {
+	Level1 uint8 // content_nibble_level_1
+	Level2 uint8 // content_nibble_level_2
+	User   uint8 // user_byte
+}
+
+func (d *Desc_54) String() string {
+	return fmt.Sprintf("0x54 content_descriptor: items=%d", len(d.Items))
+}
+
+func (d *Desc_54) Tag() uint8 {
+	return 0x54
+}
+
+func (d *Desc_54) Encode() (desc Descriptors) {
+	descLen := len(d.Items) * 2
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x54
+	desc[1] = byte(descLen)
+	for i, item := range d.Items {
+		desc[2+i*2] = (item.Level1 << 4) | (item.Level2 & 0x0F)
+		desc[3+i*2] = item.User
+	}
+	return desc
+}
+
+func (d *Desc_54) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x54 || len(desc) < 2+int(desc[1]) || desc[1]%2 != 0 {
+		return ErrDescriptorFormat
+	}
+
+	d.Items = nil
+	for skip := 2; skip < 2+int(desc[1]); skip += 2 {
+		d.Items = append(d.Items, Desc_54_Item{
+			Level1: desc[skip] >> 4,
+			Level2: desc[skip] & 0x0F,
+			User:   desc[skip+1],
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package mpegts
+
+import (
+	"fmt"
+)
+
+// Desc_55 parental_rating_descriptor
+type Desc_55 struct {
+	Items []Desc_55_Item
+}
+
+type Desc_55_Item struct {
+	Country string // ISO 3166 country code
+	Rating  uint8
+}
+
+func (d *Desc_55) String() string {
+	return fmt.Sprintf("0x55 parental_rating_descriptor: items=%d", len(d.Items))
+}
+
+func (d *Desc_55) Tag() uint8 {
+	return 0x55
+}
+
+func (d *Desc_55) Encode() (desc Descriptors) {
+	descLen := len(d.Items) * 4
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x55
+	desc[1] = byte(descLen)
+	for i, item := range d.Items {
+		putLanguage(desc[2+i*4:], item.Country)
+		desc[5+i*4] = item.Rating
+	}
+	return desc
+}
+
+func (d *Desc_55) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x55 || len(desc) < 2+int(desc[1]) || desc[1]%4 != 0 {
+		return ErrDescriptorFormat
+	}
+
+	d.Items = nil
+	for skip := 2; skip < 2+int(desc[1]); skip += 4 {
+		d.Items = append(d.Items, Desc_55_Item{
+			Country: string(desc[skip : skip+3]),
+			Rating:  desc[skip+3],
+		})
+	}
+
+	return nil
+}
+
+// MinimumAge returns minimum age for the rating.
+// Returns 0 if rating is undefined or defined by broadcaster.
+func (i Desc_55_Item) MinimumAge() int {
+	if i.Rating >= 0x01 && i.Rating <= 0x0F {
+		return int(i.Rating) + 3
+	}
+
+	return 0
+}
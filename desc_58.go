@@ -0,0 +1,103 @@
+package mpegts
+
+import (
+	"fmt"
+	"time"
+)
+
+// Desc_58 local_time_offset_descriptor
+type Desc_58 struct {
+	Items []Desc_58_Item
+}
+
+type Desc_58_Item struct {
+	Country string // ISO 3166 country code
+	Region  uint8  // 6-bit country_region_id
+
+	// Offset from UTC. Negative if local_time_offset_polarity is set
+	Offset time.Duration
+
+	// UTC time when the change of time occurs
+	TimeOfChange time.Time
+
+	// Offset from UTC after TimeOfChange.
+	// Descriptor has one polarity bit for both offsets, so NextOffset
+	// should have the same sign as Offset. Otherwise NextOffset is encoded
+	// with the sign of Offset. Sign of NextOffset is used if Offset is 0
+	NextOffset time.Duration
+}
+
+const desc58ItemSize = 13
+
+func (d *Desc_58) String() string {
+	return fmt.Sprintf("0x58 local_time_offset_descriptor: items=%d", len(d.Items))
+}
+
+func (d *Desc_58) Tag() uint8 {
+	return 0x58
+}
+
+// decodeBCDOffset returns duration from the 16-bit BCD field: hh:mm
+func decodeBCDOffset(b []byte) time.Duration {
+	return time.Duration(bcdDecode(uint32(b[0])))*time.Hour +
+		time.Duration(bcdDecode(uint32(b[1])))*time.Minute
+}
+
+// encodeBCDOffset puts absolute duration into the 16-bit BCD field: hh:mm
+func encodeBCDOffset(b []byte, d time.Duration) {
+	if d < 0 {
+		d = -d
+	}
+
+	minutes := uint32(d / time.Minute)
+	b[0] = byte(bcdEncode(minutes / 60))
+	b[1] = byte(bcdEncode(minutes % 60))
+}
+
+func (d *Desc_58) Encode() (desc Descriptors) {
+	descLen := len(d.Items) * desc58ItemSize
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x58
+	desc[1] = byte(descLen)
+
+	for i, item := range d.Items {
+		b := desc[2+i*desc58ItemSize:]
+		putLanguage(b, item.Country)
+		b[3] = ((item.Region & 0x3F) << 2) | 0x02
+		if item.Offset < 0 || (item.Offset == 0 && item.NextOffset < 0) {
+			b[3] |= 0x01
+		}
+		encodeBCDOffset(b[4:], item.Offset)
+		encodeMJD(b[6:], item.TimeOfChange)
+		encodeBCDOffset(b[11:], item.NextOffset)
+	}
+
+	return desc
+}
+
+func (d *Desc_58) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x58 || len(desc) < 2+int(desc[1]) || desc[1]%desc58ItemSize != 0 {
+		return ErrDescriptorFormat
+	}
+
+	d.Items = nil
+	for skip := 2; skip < 2+int(desc[1]); skip += desc58ItemSize {
+		b := desc[skip:]
+		item := Desc_58_Item{
+			Country:      string(b[:3]),
+			Region:       b[3] >> 2,
+			Offset:       decodeBCDOffset(b[4:]),
+			TimeOfChange: decodeMJD(b[6:]),
+			NextOffset:   decodeBCDOffset(b[11:]),
+		}
+
+		if (b[3] & 0x01) != 0 {
+			item.Offset = -item.Offset
+			item.NextOffset = -item.NextOffset
+		}
+
+		d.Items = append(d.Items, item)
+	}
+
+	return nil
+}
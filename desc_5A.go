@@ -0,0 +1,75 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_5A terrestrial_delivery_system_descriptor
+type Desc_5A struct {
+	CentreFrequency  uint32 // in 10 Hz units
+	Bandwidth        uint8  // 0 - 8MHz, 1 - 7MHz, 2 - 6MHz, 3 - 5MHz
+	Priority         bool   // true for HP stream or non-hierarchical
+	TimeSlicing      bool   // false if at least one stream uses Time Slicing
+	MPEFEC           bool   // false if at least one stream uses MPE-FEC
+	Constellation    uint8  // 0 - QPSK, 1 - 16-QAM, 2 - 64-QAM
+	Hierarchy        uint8  // hierarchy_information
+	CodeRateHP       uint8
+	CodeRateLP       uint8
+	GuardInterval    uint8 // 0 - 1/32, 1 - 1/16, 2 - 1/8, 3 - 1/4
+	TransmissionMode uint8 // 0 - 2k, 1 - 8k, 2 - 4k
+	OtherFrequency   bool
+}
+
+func (d *Desc_5A) String() string {
+	return fmt.Sprintf("0x5A terrestrial_delivery_system_descriptor: centre_frequency=%d", d.CentreFrequency)
+}
+
+func (d *Desc_5A) Tag() uint8 {
+	return 0x5A
+}
+
+func (d *Desc_5A) Encode() (desc Descriptors) {
+	desc = make(Descriptors, 13)
+	desc[0] = 0x5A
+	desc[1] = 11
+	binary.BigEndian.PutUint32(desc[2:], d.CentreFrequency)
+	desc[6] = ((d.Bandwidth & 0x07) << 5) | 0x03
+	if d.Priority {
+		desc[6] |= 0x10
+	}
+	if d.TimeSlicing {
+		desc[6] |= 0x08
+	}
+	if d.MPEFEC {
+		desc[6] |= 0x04
+	}
+	desc[7] = ((d.Constellation & 0x03) << 6) | ((d.Hierarchy & 0x07) << 3) | (d.CodeRateHP & 0x07)
+	desc[8] = ((d.CodeRateLP & 0x07) << 5) | ((d.GuardInterval & 0x03) << 3) | ((d.TransmissionMode & 0x03) << 1)
+	if d.OtherFrequency {
+		desc[8] |= 0x01
+	}
+	binary.BigEndian.PutUint32(desc[9:], 0xFFFFFFFF)
+	return desc
+}
+
+func (d *Desc_5A) Decode(desc Descriptors) error {
+	if len(desc) < 13 || desc[0] != 0x5A || desc[1] < 11 {
+		return ErrDescriptorFormat
+	}
+
+	d.CentreFrequency = binary.BigEndian.Uint32(desc[2:])
+	d.Bandwidth = desc[6] >> 5
+	d.Priority = (desc[6] & 0x10) != 0
+	d.TimeSlicing = (desc[6] & 0x08) != 0
+	d.MPEFEC = (desc[6] & 0x04) != 0
+	d.Constellation = desc[7] >> 6
+	d.Hierarchy = (desc[7] >> 3) & 0x07
+	d.CodeRateHP = desc[7] & 0x07
+	d.CodeRateLP = desc[8] >> 5
+	d.GuardInterval = (desc[8] >> 3) & 0x03
+	d.TransmissionMode = (desc[8] >> 1) & 0x03
+	d.OtherFrequency = (desc[8] & 0x01) != 0
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_5F private_data_specifier_descriptor.
+// Defines owner of the private descriptors following in the same loop.
+type Desc_5F struct {
+	Specifier uint32
+}
+
+func (d *Desc_5F) String() string {
+	return fmt.Sprintf("0x5F private_data_specifier_descriptor: private_data_specifier=0x%08X", d.Specifier)
+}
+
+func (d *Desc_5F) Tag() uint8 {
+	return 0x5F
+}
+
+func (d *Desc_5F) Encode() (desc Descriptors) {
+	desc = make(Descriptors, 6)
+	desc[0] = 0x5F
+	desc[1] = 4
+	binary.BigEndian.PutUint32(desc[2:], d.Specifier)
+	return desc
+}
+
+func (d *Desc_5F) Decode(desc Descriptors) error {
+	if len(desc) < 6 || desc[0] != 0x5F || desc[1] < 4 {
+		return ErrDescriptorFormat
+	}
+
+	d.Specifier = binary.BigEndian.Uint32(desc[2:])
+	return nil
+}
@@ -0,0 +1,93 @@
+package mpegts
+
+import (
+	"fmt"
+)
+
+// Desc_79 S2_satellite_delivery_system_descriptor.
+// Should follow satellite_delivery_system_descriptor.
+type Desc_79 struct {
+	BackwardsCompatibility bool
+
+	HasScramblingSequence bool
+	ScramblingSequence    uint32 // 18-bit scrambling_sequence_index
+
+	HasInputStream bool // multiple_input_stream_flag
+	InputStreamID  uint8
+}
+
+func (d *Desc_79) String() string {
+	return fmt.Sprintf("0x79 S2_satellite_delivery_system_descriptor: input_stream_identifier=%d", d.InputStreamID)
+}
+
+func (d *Desc_79) Tag() uint8 {
+	return 0x79
+}
+
+func (d *Desc_79) Encode() (desc Descriptors) {
+	descLen := 1
+	if d.HasScramblingSequence {
+		descLen += 3
+	}
+	if d.HasInputStream {
+		descLen += 1
+	}
+
+	desc = make(Descriptors, descLen+2)
+	desc[0] = 0x79
+	desc[1] = byte(descLen)
+	desc[2] = 0x1F
+	if d.HasScramblingSequence {
+		desc[2] |= 0x80
+	}
+	if d.HasInputStream {
+		desc[2] |= 0x40
+	}
+	if d.BackwardsCompatibility {
+		desc[2] |= 0x20
+	}
+
+	skip := 3
+	if d.HasScramblingSequence {
+		desc[3] = 0xFC | byte((d.ScramblingSequence>>16)&0x03)
+		desc[4] = byte(d.ScramblingSequence >> 8)
+		desc[5] = byte(d.ScramblingSequence)
+		skip += 3
+	}
+	if d.HasInputStream {
+		desc[skip] = d.InputStreamID
+	}
+
+	return desc
+}
+
+func (d *Desc_79) Decode(desc Descriptors) error {
+	if len(desc) < 3 || desc[0] != 0x79 || len(desc) < 2+int(desc[1]) || desc[1] < 1 {
+		return ErrDescriptorFormat
+	}
+
+	end := 2 + int(desc[1])
+
+	d.HasScramblingSequence = (desc[2] & 0x80) != 0
+	d.HasInputStream = (desc[2] & 0x40) != 0
+	d.BackwardsCompatibility = (desc[2] & 0x20) != 0
+	d.ScramblingSequence = 0
+	d.InputStreamID = 0
+
+	skip := 3
+	if d.HasScramblingSequence {
+		if skip+3 > end {
+			return ErrDescriptorFormat
+		}
+		d.ScramblingSequence = (uint32(desc[3]&0x03) << 16) | (uint32(desc[4]) << 8) | uint32(desc[5])
+		skip += 3
+	}
+	if d.HasInputStream {
+		if skip+1 > end {
+			return ErrDescriptorFormat
+		}
+		d.InputStreamID = desc[skip]
+	}
+
+	return nil
+}
@@ -22,6 +22,10 @@ func (d *Desc_7A) String() string {
 	return "0x7A enhanced_AC-3_descriptor"
 }
 
+func (d *Desc_7A) Tag() uint8 {
+	return 0x7A
+}
+
 func (d *Desc_7A) Encode() (desc Descriptors) {
 	desc = make(Descriptors, d.len+2)
 	desc[0] = 0x7A
@@ -31,47 +35,46 @@ func (d *Desc_7A) Encode() (desc Descriptors) {
 }
 
 func (d *Desc_7A) Decode(desc Descriptors) error {
-	if len(desc) >= 2 && desc[0] == 0x7A {
-		d.len = desc[1]
-		d.data = make([]byte, d.len)
-		copy(d.data, desc[2:])
-		if d.len > 0 {
-			component_pos := 1
-			d.component_flag = d.data[0]
+	if len(desc) < 2 || desc[0] != 0x7A || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
 
-			if d.ComponentTypeFlag() {
-				d.component_type = d.data[component_pos]
-				component_pos++
-			}
-			if d.BsidFlag() {
-				d.bsid = d.data[component_pos]
-				component_pos++
-			}
-			if d.MainidFlag() {
-				d.mainid = d.data[component_pos]
-				component_pos++
-			}
-			if d.AsvcFlag() {
-				d.asvc = d.data[component_pos]
-				component_pos++
-			}
-			if d.Substream1Flag() {
-				d.substream1 = d.data[component_pos]
-				component_pos++
-			}
-			if d.Substream2Flag() {
-				d.substream2 = d.data[component_pos]
-				component_pos++
-			}
-			if d.Substream3Flag() {
-				d.substream3 = d.data[component_pos]
-				component_pos++
-			}
-		}
+	*d = Desc_7A{}
+	d.len = desc[1]
+	d.data = make([]byte, d.len)
+	copy(d.data, desc[2:])
+
+	if d.len == 0 {
 		return nil
-	} else {
+	}
+
+	d.component_flag = d.data[0]
+	component_pos := 1
+
+	// next returns the next optional field if flag is set
+	next := func(flag bool, field *byte) bool {
+		if !flag {
+			return true
+		}
+		if component_pos >= len(d.data) {
+			return false
+		}
+		*field = d.data[component_pos]
+		component_pos++
+		return true
+	}
+
+	if !next(d.ComponentTypeFlag(), &d.component_type) ||
+		!next(d.BsidFlag(), &d.bsid) ||
+		!next(d.MainidFlag(), &d.mainid) ||
+		!next(d.AsvcFlag(), &d.asvc) ||
+		!next(d.Substream1Flag(), &d.substream1) ||
+		!next(d.Substream2Flag(), &d.substream2) ||
+		!next(d.Substream3Flag(), &d.substream3) {
 		return ErrDescriptorFormat
 	}
+
+	return nil
 }
 
 func (d *Desc_7A) ComponentTypeFlag() bool {
@@ -0,0 +1,173 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Desc_7F_04 T2_delivery_system_descriptor.
+// Extension descriptor with descriptor_tag_extension 0x04.
+type Desc_7F_04 struct {
+	PLPID      uint8
+	T2SystemID uint16
+
+	// Fields below are present only if HasDetails is true
+	HasDetails       bool
+	SISOMISO         uint8 // 0 - SISO, 1 - MISO
+	Bandwidth        uint8 // 0 - 8MHz, 1 - 7MHz, 2 - 6MHz, 3 - 5MHz, 4 - 10MHz, 5 - 1.712MHz
+	GuardInterval    uint8
+	TransmissionMode uint8
+	OtherFrequency   bool
+	TFS              bool // Time Frequency Slicing
+
+	Cells []Desc_7F_04_Cell
+}
+
+type Desc_7F_04_Cell struct {
+	CellID uint16
+
+	// centre_frequency in 10 Hz units.
+	// Contains one value if TFS is not used.
+	Frequencies []uint32
+
+	Subcells []Desc_7F_04_Subcell
+}
+
+type Desc_7F_04_Subcell struct {
+	CellIDExtension     uint8
+	TransposerFrequency uint32 // in 10 Hz units
+}
+
+func (d *Desc_7F_04) String() string {
+	return fmt.Sprintf("0x7F 0x04 T2_delivery_system_descriptor: plp_id=%d, T2_system_id=%d", d.PLPID, d.T2SystemID)
+}
+
+func (d *Desc_7F_04) Tag() uint8 {
+	return 0x7F
+}
+
+// ExtensionTag returns descriptor_tag_extension
+func (d *Desc_7F_04) ExtensionTag() uint8 {
+	return 0x04
+}
+
+func (d *Desc_7F_04) Encode() (desc Descriptors) {
+	desc = Descriptors{0x7F, 0x00, 0x04, d.PLPID, 0x00, 0x00}
+	binary.BigEndian.PutUint16(desc[4:], d.T2SystemID)
+
+	if d.HasDetails {
+		b0 := ((d.SISOMISO & 0x03) << 6) | ((d.Bandwidth & 0x0F) << 2) | 0x03
+		b1 := ((d.GuardInterval & 0x07) << 5) | ((d.TransmissionMode & 0x07) << 2)
+		if d.OtherFrequency {
+			b1 |= 0x02
+		}
+		if d.TFS {
+			b1 |= 0x01
+		}
+		desc = append(desc, b0, b1)
+
+		for _, cell := range d.Cells {
+			desc = binary.BigEndian.AppendUint16(desc, cell.CellID)
+
+			if d.TFS {
+				desc = append(desc, byte(len(cell.Frequencies)*4))
+				for _, f := range cell.Frequencies {
+					desc = binary.BigEndian.AppendUint32(desc, f)
+				}
+			} else {
+				f := uint32(0)
+				if len(cell.Frequencies) != 0 {
+					f = cell.Frequencies[0]
+				}
+				desc = binary.BigEndian.AppendUint32(desc, f)
+			}
+
+			desc = append(desc, byte(len(cell.Subcells)*5))
+			for _, s := range cell.Subcells {
+				desc = append(desc, s.CellIDExtension)
+				desc = binary.BigEndian.AppendUint32(desc, s.TransposerFrequency)
+			}
+		}
+	}
+
+	desc[1] = byte(len(desc) - 2)
+	return desc
+}
+
+func (d *Desc_7F_04) Decode(desc Descriptors) error {
+	if len(desc) < 6 || desc[0] != 0x7F || desc[2] != 0x04 || len(desc) < 2+int(desc[1]) || desc[1] < 4 {
+		return ErrDescriptorFormat
+	}
+
+	end := 2 + int(desc[1])
+
+	*d = Desc_7F_04{}
+	d.PLPID = desc[3]
+	d.T2SystemID = binary.BigEndian.Uint16(desc[4:])
+
+	if end == 6 {
+		return nil
+	}
+
+	if end < 8 {
+		return ErrDescriptorFormat
+	}
+
+	d.HasDetails = true
+	d.SISOMISO = desc[6] >> 6
+	d.Bandwidth = (desc[6] >> 2) & 0x0F
+	d.GuardInterval = desc[7] >> 5
+	d.TransmissionMode = (desc[7] >> 2) & 0x07
+	d.OtherFrequency = (desc[7] & 0x02) != 0
+	d.TFS = (desc[7] & 0x01) != 0
+
+	skip := 8
+	for skip < end {
+		if skip+2 > end {
+			return ErrDescriptorFormat
+		}
+
+		cell := Desc_7F_04_Cell{
+			CellID: binary.BigEndian.Uint16(desc[skip:]),
+		}
+		skip += 2
+
+		count := 1
+		if d.TFS {
+			if skip+1 > end || desc[skip]%4 != 0 {
+				return ErrDescriptorFormat
+			}
+			count = int(desc[skip]) / 4
+			skip += 1
+		}
+
+		if skip+count*4 > end {
+			return ErrDescriptorFormat
+		}
+		for i := 0; i < count; i++ {
+			cell.Frequencies = append(cell.Frequencies, binary.BigEndian.Uint32(desc[skip:]))
+			skip += 4
+		}
+
+		if skip+1 > end || desc[skip]%5 != 0 {
+			return ErrDescriptorFormat
+		}
+		count = int(desc[skip]) / 5
+		skip += 1
+
+		if skip+count*5 > end {
+			return ErrDescriptorFormat
+		}
+		for i := 0; i < count; i++ {
+			cell.Subcells = append(cell.Subcells, Desc_7F_04_Subcell{
+				CellIDExtension:     desc[skip],
+				TransposerFrequency: binary.BigEndian.Uint32(desc[skip+1:]),
+			})
+			skip += 5
+		}
+
+		d.Cells = append(d.Cells, cell)
+	}
+
+	return nil
+}
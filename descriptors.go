@@ -1,13 +1,130 @@
 package mpegts
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 type Descriptors []byte
 
+// Descriptor is a typed descriptor
+type Descriptor interface {
+	// Tag returns descriptor_tag
+	Tag() uint8
+
+	// Encode returns descriptor with tag and length
+	Encode() Descriptors
+
+	// Decode parses descriptor with tag and length
+	Decode(desc Descriptors) error
+
+	String() string
+}
+
+// DescriptorFn returns a new instance of the typed descriptor
+type DescriptorFn func() Descriptor
+
+// RawDescriptor is a descriptor without registered type
+type RawDescriptor struct {
+	Data Descriptors // descriptor with tag and length
+}
+
 var (
 	ErrDescriptorFormat = errors.New("descriptor: invalid format")
+	ErrDescriptorSize   = errors.New("descriptor: size limit")
+)
+
+// Registry of the descriptor types.
+// Should be modified only on program initialization.
+var (
+	descriptorRegistry = map[uint8]DescriptorFn{
+		0x09: func() Descriptor { return new(Desc_09) },
+		0x40: func() Descriptor { return new(Desc_40) },
+		0x41: func() Descriptor { return new(Desc_41) },
+		0x43: func() Descriptor { return new(Desc_43) },
+		0x44: func() Descriptor { return new(Desc_44) },
+		0x47: func() Descriptor { return new(Desc_47) },
+		0x48: func() Descriptor { return new(Desc_48) },
+		0x4D: func() Descriptor { return new(Desc_4D) },
+		0x4E: func() Descriptor { return new(Desc_4E) },
+		0x54: func() Descriptor { return new(Desc_54) },
+		0x55: func() Descriptor { return new(Desc_55) },
+		0x58: func() Descriptor { return new(Desc_58) },
+		0x5A: func() Descriptor { return new(Desc_5A) },
+		0x5F: func() Descriptor { return new(Desc_5F) },
+		0x79: func() Descriptor { return new(Desc_79) },
+		0x7A: func() Descriptor { return new(Desc_7A) },
+	}
+
+	// extension descriptors with tag 0x7F by descriptor_tag_extension
+	extensionRegistry = map[uint8]DescriptorFn{
+		0x04: func() Descriptor { return new(Desc_7F_04) },
+	}
+
+	// private descriptors by private_data_specifier and tag
+	privateRegistry = map[uint64]DescriptorFn{}
 )
 
+// RegisterDescriptor sets descriptor type for the tag.
+// For user defined tags 0x80-0xFE type is used if there is no
+// private descriptor type for the private_data_specifier.
+// If fn is nil descriptor type will be removed.
+func RegisterDescriptor(tag uint8, fn DescriptorFn) {
+	if fn == nil {
+		delete(descriptorRegistry, tag)
+	} else {
+		descriptorRegistry[tag] = fn
+	}
+}
+
+// RegisterExtensionDescriptor sets descriptor type
+// for the extension descriptor with descriptor_tag_extension.
+// If fn is nil descriptor type will be removed.
+func RegisterExtensionDescriptor(extension uint8, fn DescriptorFn) {
+	if fn == nil {
+		delete(extensionRegistry, extension)
+	} else {
+		extensionRegistry[extension] = fn
+	}
+}
+
+// RegisterPrivateDescriptor sets descriptor type for the tag
+// defined by the private_data_specifier.
+// Private descriptor types are used only for user defined tags 0x80-0xFE
+// after private_data_specifier_descriptor in the same loop.
+// If fn is nil descriptor type will be removed.
+func RegisterPrivateDescriptor(specifier uint32, tag uint8, fn DescriptorFn) {
+	key := uint64(specifier)<<8 | uint64(tag)
+
+	if fn == nil {
+		delete(privateRegistry, key)
+	} else {
+		privateRegistry[key] = fn
+	}
+}
+
+func lookupDescriptor(desc Descriptors, specifier uint32) DescriptorFn {
+	tag := desc[0]
+
+	switch {
+	case tag == 0x7F:
+		if desc[1] != 0 {
+			return extensionRegistry[desc[2]]
+		}
+	case tag >= 0x80 && tag != 0xFF:
+		if specifier != 0 {
+			if fn, ok := privateRegistry[uint64(specifier)<<8|uint64(tag)]; ok {
+				return fn
+			}
+		}
+		return descriptorRegistry[tag]
+	default:
+		return descriptorRegistry[tag]
+	}
+
+	return nil
+}
+
 func (d Descriptors) Check() error {
 	end := len(d)
 	skip := 0
@@ -37,3 +154,162 @@ func (d Descriptors) Next() Descriptors {
 		return d[next:]
 	}
 }
+
+// Find returns the first descriptor with tag or nil if not found.
+// Descriptors loop should be checked before.
+func (d Descriptors) Find(tag uint8) Descriptors {
+	for ; len(d) != 0; d = d.Next() {
+		if d[0] == tag {
+			return d[:2+int(d[1])]
+		}
+	}
+
+	return nil
+}
+
+// FindAll returns all descriptors with tag.
+// Descriptors loop should be checked before.
+func (d Descriptors) FindAll(tag uint8) []Descriptors {
+	var list []Descriptors
+
+	for ; len(d) != 0; d = d.Next() {
+		if d[0] == tag {
+			list = append(list, d[:2+int(d[1])])
+		}
+	}
+
+	return list
+}
+
+// Remove returns a new descriptors loop without descriptors with tag.
+// Descriptors loop should be checked before.
+func (d Descriptors) Remove(tag uint8) Descriptors {
+	result := make(Descriptors, 0, len(d))
+
+	for ; len(d) != 0; d = d.Next() {
+		if d[0] != tag {
+			result = append(result, d[:2+int(d[1])]...)
+		}
+	}
+
+	return result
+}
+
+// Replace returns a new descriptors loop with the first descriptor
+// with the same tag replaced by desc. If not found desc is appended.
+// Descriptors loop should be checked before.
+func (d Descriptors) Replace(desc Descriptors) Descriptors {
+	result := make(Descriptors, 0, len(d)+len(desc))
+	found := false
+
+	for ; len(d) != 0; d = d.Next() {
+		if !found && d[0] == desc[0] {
+			result = append(result, desc...)
+			found = true
+		} else {
+			result = append(result, d[:2+int(d[1])]...)
+		}
+	}
+
+	if !found {
+		result = append(result, desc...)
+	}
+
+	return result
+}
+
+// Insert returns a new descriptors loop with desc inserted before
+// the descriptor with index. If index is out of range desc is appended.
+// Descriptors loop should be checked before.
+func (d Descriptors) Insert(index int, desc Descriptors) Descriptors {
+	result := make(Descriptors, 0, len(d)+len(desc))
+	inserted := false
+
+	for i := 0; len(d) != 0; i++ {
+		if i == index {
+			result = append(result, desc...)
+			inserted = true
+		}
+		result = append(result, d[:2+int(d[1])]...)
+		d = d.Next()
+	}
+
+	if !inserted {
+		result = append(result, desc...)
+	}
+
+	return result
+}
+
+// Decode returns typed descriptors from the descriptors loop.
+// Descriptors without registered type or with invalid format
+// are returned as RawDescriptor.
+func (d Descriptors) Decode() ([]Descriptor, error) {
+	if err := d.Check(); err != nil {
+		return nil, err
+	}
+
+	var list []Descriptor
+	specifier := uint32(0)
+
+	for ; len(d) != 0; d = d.Next() {
+		desc := d[:2+int(d[1])]
+
+		var item Descriptor
+		if fn := lookupDescriptor(desc, specifier); fn != nil {
+			item = fn()
+			if err := item.Decode(desc); err != nil {
+				item = nil
+			}
+		}
+
+		if item == nil {
+			raw := new(RawDescriptor)
+			_ = raw.Decode(desc)
+			item = raw
+		}
+
+		if pds, ok := item.(*Desc_5F); ok {
+			specifier = pds.Specifier
+		}
+
+		list = append(list, item)
+	}
+
+	return list, nil
+}
+
+// EncodeDescriptors returns descriptors loop from the typed descriptors
+func EncodeDescriptors(list []Descriptor) Descriptors {
+	var desc Descriptors
+
+	for _, item := range list {
+		desc = append(desc, item.Encode()...)
+	}
+
+	return desc
+}
+
+func (d *RawDescriptor) String() string {
+	return fmt.Sprintf("0x%02X descriptor: % X", d.Tag(), []byte(d.Data[2:]))
+}
+
+func (d *RawDescriptor) Tag() uint8 {
+	return d.Data[0]
+}
+
+func (d *RawDescriptor) Encode() (desc Descriptors) {
+	desc = make(Descriptors, len(d.Data))
+	copy(desc, d.Data)
+	return desc
+}
+
+func (d *RawDescriptor) Decode(desc Descriptors) error {
+	if len(desc) < 2 || len(desc) < 2+int(desc[1]) {
+		return ErrDescriptorFormat
+	}
+
+	d.Data = make(Descriptors, 2+int(desc[1]))
+	copy(d.Data, desc)
+	return nil
+}
@@ -0,0 +1,277 @@
+package mpegts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPrivateDesc is a logical_channel_descriptor with private_data_specifier 0x00000028
+type testPrivateDesc struct {
+	Data []byte
+}
+
+func (d *testPrivateDesc) String() string { return "0x83 logical_channel_descriptor" }
+
+func (d *testPrivateDesc) Tag() uint8 { return 0x83 }
+
+func (d *testPrivateDesc) Encode() Descriptors {
+	return append(Descriptors{0x83, byte(len(d.Data))}, d.Data...)
+}
+
+func (d *testPrivateDesc) Decode(desc Descriptors) error {
+	if len(desc) < 2 || desc[0] != 0x83 {
+		return ErrDescriptorFormat
+	}
+	d.Data = append([]byte(nil), desc[2:2+desc[1]]...)
+	return nil
+}
+
+func TestDescriptors_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterPrivateDescriptor(0x00000028, 0x83, func() Descriptor { return new(testPrivateDesc) })
+	defer RegisterPrivateDescriptor(0x00000028, 0x83, nil)
+
+	var desc Descriptors
+	desc = append(desc, (&Desc_48{ServiceType: 0x01, ServiceProviderName: "Cesbo", ServiceName: "Test"}).Encode()...)
+	desc = append(desc, 0x83, 0x02, 0x01, 0x02) // without private_data_specifier
+	desc = append(desc, (&Desc_5F{Specifier: 0x00000028}).Encode()...)
+	desc = append(desc, 0x83, 0x04, 0x00, 0x01, 0xFC, 0x01)
+	desc = append(desc, (&Desc_7F_04{PLPID: 1, T2SystemID: 2}).Encode()...)
+	desc = append(desc, 0x7F, 0x02, 0x99, 0x00) // unknown extension
+	desc = append(desc, 0x48, 0x01, 0x00)       // invalid format
+
+	list, err := desc.Decode()
+	if !assert.NoError(err) || !assert.Equal(7, len(list)) {
+		return
+	}
+
+	if d, ok := list[0].(*Desc_48); assert.True(ok) {
+		assert.Equal("Cesbo", d.ServiceProviderName)
+		assert.Equal("Test", d.ServiceName)
+	}
+
+	if d, ok := list[1].(*RawDescriptor); assert.True(ok) {
+		assert.Equal(uint8(0x83), d.Tag())
+		assert.Equal(Descriptors{0x83, 0x02, 0x01, 0x02}, d.Data)
+	}
+
+	if d, ok := list[2].(*Desc_5F); assert.True(ok) {
+		assert.Equal(uint32(0x00000028), d.Specifier)
+	}
+
+	if d, ok := list[3].(*testPrivateDesc); assert.True(ok) {
+		assert.Equal([]byte{0x00, 0x01, 0xFC, 0x01}, d.Data)
+	}
+
+	if d, ok := list[4].(*Desc_7F_04); assert.True(ok) {
+		assert.Equal(uint8(1), d.PLPID)
+		assert.Equal(uint16(2), d.T2SystemID)
+	}
+
+	_, ok := list[5].(*RawDescriptor)
+	assert.True(ok)
+	assert.Equal(uint8(0x7F), list[5].Tag())
+
+	_, ok = list[6].(*RawDescriptor)
+	assert.True(ok)
+
+	// unknown and invalid descriptors are preserved
+	assert.Equal(desc, EncodeDescriptors(list))
+
+	_, err = Descriptors{0x48, 0x05, 0x00}.Decode()
+	assert.ErrorIs(err, ErrDescriptorFormat)
+}
+
+func TestDescriptors_DecodeUserDefined(t *testing.T) {
+	assert := assert.New(t)
+
+	RegisterDescriptor(0x83, func() Descriptor { return new(testPrivateDesc) })
+	defer RegisterDescriptor(0x83, nil)
+
+	RegisterPrivateDescriptor(0x00000028, 0x83, func() Descriptor { return new(RawDescriptor) })
+	defer RegisterPrivateDescriptor(0x00000028, 0x83, nil)
+
+	var desc Descriptors
+	desc = append(desc, 0x83, 0x02, 0x01, 0x02) // without private_data_specifier
+	desc = append(desc, (&Desc_5F{Specifier: 0x00000029}).Encode()...)
+	desc = append(desc, 0x83, 0x01, 0x03) // without private descriptor type
+	desc = append(desc, (&Desc_5F{Specifier: 0x00000028}).Encode()...)
+	desc = append(desc, 0x83, 0x01, 0x04) // private descriptor type
+
+	list, err := desc.Decode()
+	if !assert.NoError(err) || !assert.Equal(5, len(list)) {
+		return
+	}
+
+	if d, ok := list[0].(*testPrivateDesc); assert.True(ok) {
+		assert.Equal([]byte{0x01, 0x02}, d.Data)
+	}
+
+	if d, ok := list[2].(*testPrivateDesc); assert.True(ok) {
+		assert.Equal([]byte{0x03}, d.Data)
+	}
+
+	_, ok := list[4].(*RawDescriptor)
+	assert.True(ok)
+
+	assert.Equal(desc, EncodeDescriptors(list))
+}
+
+func TestDescriptors_Edit(t *testing.T) {
+	assert := assert.New(t)
+
+	desc := Descriptors{
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x00,
+		0x0A, 0x04, 'e', 'n', 'g', 0x00,
+		0x09, 0x04, 0x02, 0x00, 0xE2, 0x00,
+	}
+
+	assert.Equal(Descriptors{0x0A, 0x04, 'e', 'n', 'g', 0x00}, desc.Find(0x0A))
+	assert.Nil(desc.Find(0x52))
+
+	list := desc.FindAll(0x09)
+	if assert.Equal(2, len(list)) {
+		assert.Equal(Descriptors{0x09, 0x04, 0x01, 0x00, 0xE1, 0x00}, list[0])
+		assert.Equal(Descriptors{0x09, 0x04, 0x02, 0x00, 0xE2, 0x00}, list[1])
+	}
+
+	assert.Equal(Descriptors{0x0A, 0x04, 'e', 'n', 'g', 0x00}, desc.Remove(0x09))
+
+	assert.Equal(Descriptors{
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x00,
+		0x0A, 0x04, 'r', 'u', 's', 0x00,
+		0x09, 0x04, 0x02, 0x00, 0xE2, 0x00,
+	}, desc.Replace(Descriptors{0x0A, 0x04, 'r', 'u', 's', 0x00}))
+
+	assert.Equal(Descriptors{
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x00,
+		0x0A, 0x04, 'e', 'n', 'g', 0x00,
+		0x09, 0x04, 0x02, 0x00, 0xE2, 0x00,
+		0x52, 0x01, 0x01,
+	}, desc.Replace(Descriptors{0x52, 0x01, 0x01}))
+
+	assert.Equal(Descriptors{
+		0x52, 0x01, 0x01,
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x00,
+		0x0A, 0x04, 'e', 'n', 'g', 0x00,
+		0x09, 0x04, 0x02, 0x00, 0xE2, 0x00,
+	}, desc.Insert(0, Descriptors{0x52, 0x01, 0x01}))
+
+	assert.Equal(Descriptors{
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x00,
+		0x0A, 0x04, 'e', 'n', 'g', 0x00,
+		0x09, 0x04, 0x02, 0x00, 0xE2, 0x00,
+		0x52, 0x01, 0x01,
+	}, desc.Insert(10, Descriptors{0x52, 0x01, 0x01}))
+
+	// source loop is not modified
+	assert.Equal(18, len(desc))
+}
+
+func TestDesc_7A_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	// component_type, bsid, and substream1 flags
+	desc := Descriptors{0x7A, 0x04, 0xC4, 0x40, 0x10, 0x20}
+
+	d := new(Desc_7A)
+	if assert.NoError(d.Decode(desc)) {
+		assert.True(d.ComponentTypeFlag())
+		assert.True(d.BsidFlag())
+		assert.True(d.Substream1Flag())
+		assert.Equal(desc, d.Encode())
+	}
+
+	// flags without fields
+	assert.ErrorIs(d.Decode(Descriptors{0x7A, 0x01, 0xFF}), ErrDescriptorFormat)
+	assert.ErrorIs(d.Decode(desc[:5]), ErrDescriptorFormat)
+
+	list, err := Descriptors{0x7A, 0x01, 0xFF}.Decode()
+	if assert.NoError(err) && assert.Equal(1, len(list)) {
+		_, ok := list[0].(*RawDescriptor)
+		assert.True(ok)
+	}
+}
+
+func TestDescriptors_Identity(t *testing.T) {
+	assert := assert.New(t)
+
+	// descriptor for each registered tag with reserved bits set as encoder does
+	list := []Descriptors{
+		{0x09, 0x06, 0x06, 0x04, 0xE1, 0x00, 0xAA, 0xBB},
+		{0x40, 0x05, 'C', 'e', 's', 'b', 'o'},
+		{0x41, 0x06, 0x00, 0x01, 0x01, 0x00, 0x02, 0x02},
+		{0x43, 0x0B, 0x01, 0x17, 0x00, 0x00, 0x01, 0x30, 0x86, 0x02, 0x75, 0x00, 0x03},
+		{0x44, 0x0B, 0x03, 0x46, 0x00, 0x00, 0xFF, 0xF2, 0x03, 0x00, 0x69, 0x00, 0x05},
+		{0x47, 0x04, 'T', 'e', 's', 't'},
+		{0x48, 0x0B, 0x02, 0x05, 'C', 'e', 's', 'b', 'o', 0x03, 'F', 'M', '1'},
+		{0x4D, 0x0C, 'e', 'n', 'g', 0x04, 'N', 'e', 'w', 's', 0x03, 'a', 'b', 'c'},
+		{0x4E, 0x0C, 0x01, 'e', 'n', 'g', 0x04, 0x01, 'A', 0x01, 'B', 0x02, 'x', 'y'},
+		{0x54, 0x02, 0x11, 0x00},
+		{0x55, 0x04, 'G', 'B', 'R', 0x0C},
+		{0x58, 0x0D, 'R', 'U', 'S', 0x03, 0x03, 0x00, 0xC0, 0x79, 0x12, 0x45, 0x00, 0x04, 0x00},
+		{0x5A, 0x0B, 0x02, 0xFA, 0xF0, 0x80, 0x1F, 0x82, 0x02, 0xFF, 0xFF, 0xFF, 0xFF},
+		{0x5F, 0x04, 0x00, 0x00, 0x00, 0x28},
+		{0x79, 0x05, 0xDF, 0xFC, 0x00, 0x01, 0x05},
+		{0x7A, 0x04, 0xC4, 0x40, 0x10, 0x20},
+		{0x7F, 0x04, 0x04, 0x01, 0x00, 0x02},
+		{0x7F, 0x0D, 0x04, 0x01, 0x00, 0x02, 0x03, 0x04, 0x00, 0x01, 0x02, 0xFA, 0xF0, 0x80, 0x00},
+	}
+
+	tags := map[uint8]bool{}
+	for _, desc := range list {
+		tags[desc[0]] = true
+
+		items, err := desc.Decode()
+		if !assert.NoError(err) || !assert.Equal(1, len(items)) {
+			continue
+		}
+
+		_, raw := items[0].(*RawDescriptor)
+		assert.False(raw, "0x%02X", desc[0])
+		assert.Equal(desc, items[0].Encode(), "0x%02X", desc[0])
+	}
+
+	for tag := range descriptorRegistry {
+		assert.True(tags[tag], "0x%02X", tag)
+	}
+}
+
+func TestDescriptors_TextLimit(t *testing.T) {
+	// Cyrillic and Arabic are coded with UCS-2: 2 bytes per character
+	long := strings.Repeat("Жع", 100)
+
+	check := func(t *testing.T, d Descriptor) Descriptor {
+		desc := d.Encode()
+		assert.Equal(t, 2+int(desc[1]), len(desc))
+		assert.NoError(t, desc.Check())
+
+		result := descriptorRegistry[d.Tag()]()
+		assert.NoError(t, result.Decode(desc))
+		return result
+	}
+
+	t.Run("network name", func(t *testing.T) {
+		d := check(t, &Desc_40{NetworkName: long}).(*Desc_40)
+		assert.True(t, strings.HasPrefix(long, d.NetworkName))
+		assert.Equal(t, 127, len([]rune(d.NetworkName)))
+	})
+
+	t.Run("bouquet name", func(t *testing.T) {
+		d := check(t, &Desc_47{BouquetName: strings.Repeat("a", 300)}).(*Desc_47)
+		assert.Equal(t, strings.Repeat("a", 0xFF), d.BouquetName)
+	})
+
+	t.Run("service names", func(t *testing.T) {
+		d := check(t, &Desc_48{ServiceType: 0x01, ServiceProviderName: long, ServiceName: "Test"}).(*Desc_48)
+		assert.True(t, strings.HasPrefix(long, d.ServiceProviderName))
+		assert.True(t, strings.HasPrefix("Test", d.ServiceName))
+
+		d = check(t, &Desc_48{ServiceType: 0x01, ServiceProviderName: "Cesbo", ServiceName: long}).(*Desc_48)
+		assert.Equal(t, "Cesbo", d.ServiceProviderName)
+		assert.True(t, strings.HasPrefix(long, d.ServiceName))
+	})
+}
@@ -0,0 +1,526 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+const (
+	EitHeaderSize  = 14
+	EitMaximumSize = 4096
+	EitItemSize    = 12
+
+	// Number of sections in the EIT schedule segment
+	eitSegmentSections = 8
+
+	// Number of segments in the EIT schedule table. Each segment is 3 hours
+	eitTableSegments = 32
+
+	// Maximum size of the event descriptors to fit into the section
+	eitDescriptorsMaximumSize = EitMaximumSize - EitHeaderSize - EitItemSize - crc32.Size
+)
+
+// EIT is Event Information Table.
+// Table ID 0x4E/0x4F for present/following events of the actual/other TS,
+// 0x50-0x5F/0x60-0x6F for event schedule of the actual/other TS.
+type EIT struct {
+	header []byte
+	Items  []*EitItem
+
+	// received sections to check table completion
+	received    [256]bool
+	segmentLast [eitTableSegments]uint8
+
+	// sections prepared for packetizer
+	sections []eitSection
+	section  int
+}
+
+// EIT Item contains information about event
+type EitItem struct {
+	header []byte
+}
+
+type eitSection struct {
+	number      uint8 // section_number
+	segmentLast uint8 // segment_last_section_number
+	first       int   // first item
+	count       int   // number of items
+}
+
+var (
+	ErrEitFormat = errors.New("eit: invalid format")
+	ErrEitSize   = errors.New("eit: size limit")
+)
+
+var (
+	emptyEit = []byte{
+		0x4E,        // table_id
+		0xF0,        // section_length 1
+		0x00,        // section_length 2
+		0x00,        // service_id 1
+		0x00,        // service_id 2
+		0xC0 | 0x01, // version
+		0x00,        // section_number
+		0x00,        // last_section_number
+		0x00,        // transport_stream_id 1
+		0x00,        // transport_stream_id 2
+		0x00,        // original_network_id 1
+		0x00,        // original_network_id 2
+		0x00,        // segment_last_section_number
+		0x4E,        // last_table_id
+	}
+	emptyEitItem = []byte{
+		0x00, // event_id 1
+		0x00, // event_id 2
+		0xFF, // start_time 1
+		0xFF, // start_time 2
+		0xFF, // start_time 3
+		0xFF, // start_time 4
+		0xFF, // start_time 5
+		0x00, // duration 1
+		0x00, // duration 2
+		0x00, // duration 3
+		0x00, // running_status | free_CA_mode | descriptors_loop_length 1
+		0x00, // descriptors_loop_length 2
+	}
+)
+
+// NewEit returns a new EIT for present/following events of the actual TS.
+// Use SetTableID to change table type.
+func NewEit() *EIT {
+	e := new(EIT)
+	e.header = make([]byte, len(emptyEit))
+	copy(e.header, emptyEit)
+
+	return e
+}
+
+// ParseEitSection parses EIT section and appends events to the Items.
+// Repeated sections are ignored.
+func (e *EIT) ParseEitSection(b []byte) error {
+	if len(b) < (EitHeaderSize + crc32.Size) {
+		return ErrEitFormat
+	}
+
+	if e.received[b[6]] {
+		return nil
+	}
+
+	next := EitHeaderSize
+	end := len(b) - crc32.Size
+
+	var items []*EitItem
+	skip := next
+
+	for skip < end {
+		next += EitItemSize
+		if next > end {
+			return ErrEitFormat
+		}
+
+		descLen := binary.BigEndian.Uint16(b[skip+10:]) & 0x0FFF
+		if descLen > 0 {
+			next += int(descLen)
+			if next > end {
+				return ErrEitFormat
+			}
+
+			desc := Descriptors(b[skip+EitItemSize : next])
+			if err := desc.Check(); err != nil {
+				return fmt.Errorf("eit: %w", err)
+			}
+		}
+
+		item := new(EitItem)
+		item.header = make([]byte, next-skip)
+		copy(item.header, b[skip:])
+
+		items = append(items, item)
+
+		skip = next
+	}
+
+	// copy header from the first received section
+	if e.header == nil || b[6] == 0 {
+		e.header = make([]byte, EitHeaderSize)
+		copy(e.header, b)
+	}
+
+	e.Items = append(e.Items, items...)
+	e.received[b[6]] = true
+	e.segmentLast[b[6]/eitSegmentSections] = b[12]
+
+	return nil
+}
+
+// Complete checks that all sections of the table are received.
+// For schedule table each segment up to the last_section_number
+// should contain sections up to the segment_last_section_number.
+func (e *EIT) Complete() bool {
+	if e.header == nil {
+		return false
+	}
+
+	last := int(e.header[7])
+
+	for segment := 0; segment <= last/eitSegmentSections; segment++ {
+		first := segment * eitSegmentSections
+		if !e.received[first] {
+			return false
+		}
+
+		for i := first + 1; i <= int(e.segmentLast[segment]); i++ {
+			if !e.received[i] {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (e *EIT) TableID() uint8 {
+	return e.header[0]
+}
+
+// SetTableID sets table_id and last_table_id
+func (e *EIT) SetTableID(id uint8) {
+	e.header[0] = id
+	e.header[13] = id
+}
+
+// Returns true for EIT of the actual TS
+func (e *EIT) Actual() bool {
+	id := e.header[0]
+	return id == 0x4E || (id >= 0x50 && id <= 0x5F)
+}
+
+// Returns true for EIT present/following
+func (e *EIT) IsPresentFollowing() bool {
+	return e.header[0] == 0x4E || e.header[0] == 0x4F
+}
+
+// Returns true for EIT schedule
+func (e *EIT) IsSchedule() bool {
+	return e.header[0] >= 0x50 && e.header[0] <= 0x6F
+}
+
+func (e *EIT) Version() uint8 {
+	return (e.header[5] & 0x3E) >> 1
+}
+
+func (e *EIT) SetVersion(version uint8) {
+	e.header[5] &^= 0x3E
+	e.header[5] |= (version << 1) & 0x3E
+}
+
+func (e *EIT) ServiceID() uint16 {
+	return binary.BigEndian.Uint16(e.header[3:])
+}
+
+func (e *EIT) SetServiceID(id uint16) {
+	binary.BigEndian.PutUint16(e.header[3:], id)
+}
+
+func (e *EIT) TSID() uint16 {
+	return binary.BigEndian.Uint16(e.header[8:])
+}
+
+func (e *EIT) SetTSID(tsid uint16) {
+	binary.BigEndian.PutUint16(e.header[8:], tsid)
+}
+
+func (e *EIT) ONID() uint16 {
+	return binary.BigEndian.Uint16(e.header[10:])
+}
+
+func (e *EIT) SetONID(onid uint16) {
+	binary.BigEndian.PutUint16(e.header[10:], onid)
+}
+
+func (e *EIT) LastTableID() uint8 {
+	return e.header[13]
+}
+
+func (e *EIT) SetLastTableID(id uint8) {
+	e.header[13] = id
+}
+
+// prepare splits items to sections.
+// Present/following table has two sections: present event and following event.
+// Schedule table splits events into 3-hour segments counted from
+// midnight of the first event day. Items should be sorted by start time.
+// Segments without events have one empty section.
+// Table has 32 segments, events after 4 days from the first event day
+// should be in the next schedule table_id.
+// Returns ErrEitSize if some items do not fit into the table,
+// such items are skipped.
+func (e *EIT) prepare() error {
+	e.sections = e.sections[:0]
+	e.section = 0
+
+	if !e.IsSchedule() {
+		for i := 0; i < 2; i++ {
+			s := eitSection{number: uint8(i), segmentLast: 1, first: i}
+			if i < len(e.Items) {
+				s.count = 1
+			}
+			e.sections = append(e.sections, s)
+		}
+
+		if len(e.Items) > 2 {
+			return ErrEitSize
+		}
+		return nil
+	}
+
+	if len(e.Items) == 0 {
+		e.sections = append(e.sections, eitSection{})
+		return nil
+	}
+
+	var day time.Time
+	if t := e.Items[0].StartTime(); !t.IsZero() {
+		day = t.Truncate(24 * time.Hour)
+	}
+
+	segment := -1
+	size := 0
+	begin := 0
+	var err error
+
+	for i, item := range e.Items {
+		is := len(item.header)
+
+		n := 0
+		if t := item.StartTime(); !t.IsZero() && !day.IsZero() {
+			n = int(t.Sub(day) / (3 * time.Hour))
+		}
+		if n < segment {
+			n = segment
+		} else if n >= eitTableSegments {
+			// events after 4 days should be in the next table_id
+			err = ErrEitSize
+			break
+		}
+
+		switch {
+		case n != segment:
+			for segment += 1; segment < n; segment++ {
+				number := uint8(segment * eitSegmentSections)
+				e.sections = append(e.sections, eitSection{
+					number:      number,
+					segmentLast: number,
+					first:       i,
+				})
+			}
+
+			begin = len(e.sections)
+			e.sections = append(e.sections, eitSection{
+				number: uint8(segment * eitSegmentSections),
+				first:  i,
+			})
+			size = EitHeaderSize + crc32.Size
+		case (size + is) > EitMaximumSize:
+			last := e.sections[len(e.sections)-1]
+			if (last.number % eitSegmentSections) == (eitSegmentSections - 1) {
+				// no more sections in the segment.
+				// keep size on the limit to skip following items of the segment
+				size = EitMaximumSize
+				err = ErrEitSize
+				continue
+			}
+
+			e.sections = append(e.sections, eitSection{
+				number: last.number + 1,
+				first:  i,
+			})
+			size = EitHeaderSize + crc32.Size
+		}
+
+		size += is
+		e.sections[len(e.sections)-1].count += 1
+
+		// update segment_last_section_number for the segment
+		last := e.sections[len(e.sections)-1].number
+		for j := begin; j < len(e.sections); j++ {
+			e.sections[j].segmentLast = last
+		}
+	}
+
+	return err
+}
+
+// Calculates LastSectionNumber.
+// Returns ErrEitSize if present/following table has more than 2 items,
+// if items of the schedule segment do not fit into 8 sections,
+// or if items are after the last segment of the schedule table.
+// Such items are not packetized.
+func (e *EIT) Finalize() error {
+	err := e.prepare()
+
+	e.header[6] = 0
+	e.header[7] = e.sections[len(e.sections)-1].number
+
+	return err
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from EIT
+func (e *EIT) Packetizer() *PsiPacketizer {
+	_ = e.prepare()
+	return newPsiPacketizer(e)
+}
+
+func (e *EIT) sectionSize(i int) int {
+	if e.section == len(e.sections) {
+		return 0
+	}
+
+	s := &e.sections[e.section]
+	size := EitHeaderSize + crc32.Size
+	for _, item := range e.Items[s.first : s.first+s.count] {
+		size += len(item.header)
+	}
+
+	return size
+}
+
+func (e *EIT) sectionHeader(i int) []byte {
+	s := &e.sections[e.section]
+	e.section += 1
+
+	e.header[6] = s.number
+	e.header[12] = s.segmentLast
+
+	return e.header[:EitHeaderSize]
+}
+
+func (e *EIT) sectionItem(i int) []byte {
+	if i == -1 {
+		return []byte{}
+	}
+
+	if e.section == 0 {
+		return nil
+	}
+
+	s := &e.sections[e.section-1]
+	if i < s.first {
+		// skipped items
+		return []byte{}
+	}
+	if i < s.first+s.count {
+		return e.Items[i].header
+	}
+
+	return nil
+}
+
+func NewEitItem() *EitItem {
+	p := new(EitItem)
+	p.header = make([]byte, len(emptyEitItem))
+	copy(p.header, emptyEitItem)
+
+	return p
+}
+
+func (e *EitItem) EventID() uint16 {
+	return binary.BigEndian.Uint16(e.header[0:])
+}
+
+func (e *EitItem) SetEventID(id uint16) {
+	binary.BigEndian.PutUint16(e.header[0:], id)
+}
+
+// StartTime returns event start time in UTC.
+// Returns zero time if start time is undefined.
+func (e *EitItem) StartTime() time.Time {
+	return decodeMJD(e.header[2:])
+}
+
+// SetStartTime sets event start time.
+// Zero time means undefined start time.
+func (e *EitItem) SetStartTime(t time.Time) {
+	encodeMJD(e.header[2:], t)
+}
+
+func (e *EitItem) Duration() time.Duration {
+	return decodeBCDDuration(e.header[7:])
+}
+
+func (e *EitItem) SetDuration(d time.Duration) {
+	encodeBCDDuration(e.header[7:], d)
+}
+
+// RunningStatus returns event status:
+// 1 - not running, 2 - starts in a few seconds, 3 - pausing, 4 - running
+func (e *EitItem) RunningStatus() uint8 {
+	return (e.header[10] & 0xE0) >> 5
+}
+
+func (e *EitItem) SetRunningStatus(status uint8) {
+	e.header[10] &^= 0xE0
+	e.header[10] |= (status << 5) & 0xE0
+}
+
+// Returns true if one or more streams may be controlled by a CA system
+// free_CA_mode
+func (e *EitItem) IsScrambled() bool {
+	return (e.header[10] & 0x10) != 0
+}
+
+func (e *EitItem) SetScrambled(flag bool) {
+	if flag {
+		e.header[10] |= 0x10
+	} else {
+		e.header[10] &^= 0x10
+	}
+}
+
+func (e *EitItem) Descriptors() Descriptors {
+	return Descriptors(e.header[EitItemSize:])
+}
+
+// Appends descriptors to the event descriptors.
+// Returns ErrDescriptorSize if event does not fit into the section.
+func (e *EitItem) AppendDescriptors(desc Descriptors) error {
+	if len(desc) == 0 {
+		return nil
+	}
+
+	ds := len(e.header) - EitItemSize + len(desc)
+	if ds > eitDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+	e.header = append(e.header, desc...)
+
+	e.setDescriptorsLength(ds)
+	return nil
+}
+
+// SetDescriptors replaces event descriptors
+func (e *EitItem) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > eitDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+
+	e.header = append(e.header[:EitItemSize:EitItemSize], desc...)
+
+	e.setDescriptorsLength(len(desc))
+	return nil
+}
+
+func (e *EitItem) setDescriptorsLength(ds int) {
+	b := uint16(e.header[10]&0xF0) << 8
+	b |= uint16(ds)
+	binary.BigEndian.PutUint16(e.header[10:], b)
+}
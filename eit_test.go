@@ -0,0 +1,406 @@
+package mpegts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIT_MJD(t *testing.T) {
+	assert := assert.New(t)
+
+	// EN 300 468 : Annex C. 93/10/13 12:45:00
+	b := []byte{0xC0, 0x79, 0x12, 0x45, 0x00}
+	expected := time.Date(1993, 10, 13, 12, 45, 0, 0, time.UTC)
+	assert.Equal(expected, decodeMJD(b))
+
+	out := make([]byte, 5)
+	encodeMJD(out, expected)
+	assert.Equal(b, out)
+
+	encodeMJD(out, time.Time{})
+	assert.True(decodeMJD(out).IsZero())
+
+	// duration 01:45:30
+	b = []byte{0x01, 0x45, 0x30}
+	assert.Equal(time.Hour+45*time.Minute+30*time.Second, decodeBCDDuration(b))
+
+	out = make([]byte, 3)
+	encodeBCDDuration(out, time.Hour+45*time.Minute+30*time.Second)
+	assert.Equal(b, out)
+}
+
+func makeTestEitItem(id uint16, start time.Time, desc Descriptors) *EitItem {
+	item := NewEitItem()
+	item.SetEventID(id)
+	item.SetStartTime(start)
+	item.SetDuration(30 * time.Minute)
+	item.SetRunningStatus(4)
+	item.AppendDescriptors(desc)
+	return item
+}
+
+func assembleEit(t *testing.T, eit *EIT) (*EIT, int) {
+	psi := new(PSI)
+	decoded := new(EIT)
+	sections := 0
+
+	ts := NewTS(18)
+	for p := eit.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NoError(t, decoded.ParseEitSection(psi.Payload()))
+			sections += 1
+		})
+	}
+
+	return decoded, sections
+}
+
+func TestEIT_PresentFollowing(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	eit := NewEit()
+	eit.SetVersion(5)
+	eit.SetServiceID(101)
+	eit.SetTSID(1)
+	eit.SetONID(2)
+
+	name := &Desc_4D{Language: "eng", EventName: "News", Text: "Daily news"}
+	eit.Items = append(eit.Items, makeTestEitItem(1, start, name.Encode()))
+	eit.Items = append(eit.Items, makeTestEitItem(2, start.Add(30*time.Minute), nil))
+	eit.Finalize()
+
+	decoded, sections := assembleEit(t, eit)
+	assert.Equal(2, sections)
+	assert.True(decoded.Complete())
+
+	assert.True(decoded.Actual())
+	assert.True(decoded.IsPresentFollowing())
+	assert.Equal(uint8(0x4E), decoded.TableID())
+	assert.Equal(uint8(5), decoded.Version())
+	assert.Equal(uint16(101), decoded.ServiceID())
+	assert.Equal(uint16(1), decoded.TSID())
+	assert.Equal(uint16(2), decoded.ONID())
+
+	if !assert.Equal(2, len(decoded.Items)) {
+		return
+	}
+
+	item := decoded.Items[0]
+	assert.Equal(uint16(1), item.EventID())
+	assert.Equal(start, item.StartTime())
+	assert.Equal(30*time.Minute, item.Duration())
+	assert.Equal(uint8(4), item.RunningStatus())
+	assert.False(item.IsScrambled())
+
+	desc := new(Desc_4D)
+	assert.NoError(desc.Decode(item.Descriptors()))
+	assert.Equal(*name, *desc)
+
+	assert.Equal(uint16(2), decoded.Items[1].EventID())
+
+	// repeated section is ignored
+	eit = NewEit()
+	eit.Items = append(eit.Items, makeTestEitItem(1, start, nil))
+	eit.Finalize()
+
+	psi := new(PSI)
+	decoded = new(EIT)
+	ts := NewTS(18)
+	p := eit.Packetizer()
+	p.Next(ts)
+	psi.Assemble(ts, func(err error) {
+		assert.NoError(err)
+		assert.NoError(decoded.ParseEitSection(psi.Payload()))
+		assert.NoError(decoded.ParseEitSection(psi.Payload()))
+	})
+	assert.Equal(1, len(decoded.Items))
+	assert.False(decoded.Complete())
+}
+
+func TestEIT_Schedule(t *testing.T) {
+	assert := assert.New(t)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	eit := NewEit()
+	eit.SetTableID(0x50)
+	eit.SetServiceID(101)
+
+	// 3 events in the first segment, 1 event in the third segment
+	eit.Items = append(eit.Items, makeTestEitItem(1, day, nil))
+	eit.Items = append(eit.Items, makeTestEitItem(2, day.Add(time.Hour), nil))
+	eit.Items = append(eit.Items, makeTestEitItem(3, day.Add(2*time.Hour), nil))
+	eit.Items = append(eit.Items, makeTestEitItem(4, day.Add(7*time.Hour), nil))
+	assert.NoError(eit.Finalize())
+
+	// empty section for the segment 1
+	assert.Equal([]eitSection{
+		{number: 0, segmentLast: 0, first: 0, count: 3},
+		{number: 8, segmentLast: 8, first: 3, count: 0},
+		{number: 16, segmentLast: 16, first: 3, count: 1},
+	}, eit.sections)
+	assert.Equal(uint8(16), eit.header[7])
+
+	decoded, sections := assembleEit(t, eit)
+	assert.Equal(3, sections)
+	assert.True(decoded.IsSchedule())
+	assert.Equal(4, len(decoded.Items))
+	assert.True(decoded.Complete())
+
+	// first event in the segment 2
+	eit.Items = eit.Items[3:]
+	assert.NoError(eit.Finalize())
+	assert.Equal([]eitSection{
+		{number: 0, segmentLast: 0, first: 0, count: 0},
+		{number: 8, segmentLast: 8, first: 0, count: 0},
+		{number: 16, segmentLast: 16, first: 0, count: 1},
+	}, eit.sections)
+
+	decoded, sections = assembleEit(t, eit)
+	assert.Equal(3, sections)
+	assert.Equal(1, len(decoded.Items))
+	assert.True(decoded.Complete())
+}
+
+func TestEIT_ScheduleSplit(t *testing.T) {
+	assert := assert.New(t)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	text := make([]byte, 200)
+	for i := range text {
+		text[i] = 'a'
+	}
+	desc := (&Desc_4D{Language: "eng", EventName: "Event", Text: string(text)}).Encode()
+
+	eit := NewEit()
+	eit.SetTableID(0x50)
+	for i := 0; i < 40; i++ {
+		eit.Items = append(eit.Items, makeTestEitItem(uint16(i), day.Add(time.Duration(i)*time.Minute), desc))
+	}
+	eit.Finalize()
+
+	if assert.Equal(3, len(eit.sections)) {
+		for i, s := range eit.sections {
+			assert.Equal(uint8(i), s.number)
+			assert.Equal(uint8(2), s.segmentLast)
+		}
+	}
+
+	decoded, sections := assembleEit(t, eit)
+	assert.Equal(3, sections)
+	assert.True(decoded.Complete())
+	assert.Equal(40, len(decoded.Items))
+}
+
+func TestEIT_SizeLimit(t *testing.T) {
+	t.Run("schedule segment", func(t *testing.T) {
+		assert := assert.New(t)
+
+		day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		text := make([]byte, 240)
+		for i := range text {
+			text[i] = 'a'
+		}
+		desc := (&Desc_4D{Language: "eng", EventName: "Event", Text: string(text)}).Encode()
+
+		// 9 sections required for the first segment
+		eit := NewEit()
+		eit.SetTableID(0x50)
+		for i := 0; i < 9*16; i++ {
+			eit.Items = append(eit.Items, makeTestEitItem(uint16(i), day.Add(time.Duration(i)*time.Second), desc))
+		}
+		eit.Items = append(eit.Items, makeTestEitItem(1000, day.Add(3*time.Hour), nil))
+		assert.ErrorIs(eit.Finalize(), ErrEitSize)
+
+		if assert.Equal(9, len(eit.sections)) {
+			for i, s := range eit.sections[:8] {
+				assert.Equal(uint8(i), s.number)
+				assert.Equal(uint8(7), s.segmentLast)
+			}
+			assert.Equal(eitSection{number: 8, segmentLast: 8, first: 9 * 16, count: 1}, eit.sections[8])
+		}
+
+		decoded, sections := assembleEit(t, eit)
+		assert.Equal(9, sections)
+		assert.True(decoded.Complete())
+		assert.Equal(uint16(1000), decoded.Items[len(decoded.Items)-1].EventID())
+	})
+
+	t.Run("schedule table", func(t *testing.T) {
+		assert := assert.New(t)
+
+		day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+		// event on the 5th day is out of 32 segments
+		eit := NewEit()
+		eit.SetTableID(0x50)
+		eit.Items = append(eit.Items, makeTestEitItem(1, day.Add(2*time.Hour), nil))
+		eit.Items = append(eit.Items, makeTestEitItem(2, day.Add(4*24*time.Hour-time.Hour), nil))
+		eit.Items = append(eit.Items, makeTestEitItem(3, day.Add(4*24*time.Hour+time.Hour), nil))
+		assert.ErrorIs(eit.Finalize(), ErrEitSize)
+
+		if assert.Equal(32, len(eit.sections)) {
+			assert.Equal(eitSection{number: 31 * 8, segmentLast: 31 * 8, first: 1, count: 1}, eit.sections[31])
+		}
+
+		decoded, sections := assembleEit(t, eit)
+		assert.Equal(32, sections)
+		assert.True(decoded.Complete())
+		if assert.Equal(2, len(decoded.Items)) {
+			assert.Equal(uint16(2), decoded.Items[1].EventID())
+		}
+	})
+
+	t.Run("present/following", func(t *testing.T) {
+		assert := assert.New(t)
+
+		start := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+		eit := NewEit()
+		for i := 0; i < 3; i++ {
+			eit.Items = append(eit.Items, makeTestEitItem(uint16(i), start.Add(time.Duration(i)*time.Hour), nil))
+		}
+		assert.ErrorIs(eit.Finalize(), ErrEitSize)
+
+		decoded, sections := assembleEit(t, eit)
+		assert.Equal(2, sections)
+		assert.Equal(2, len(decoded.Items))
+	})
+}
+
+func TestEIT_Descriptors(t *testing.T) {
+	t.Run("short_event", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// ISO/IEC 8859-5 text
+		desc := Descriptors{
+			0x4D, 0x0C, 0x72, 0x75, 0x73, 0x04, 0x01, 0xDD,
+			0xD5, 0xE2, 0x03, 0x41, 0x42, 0x43,
+		}
+
+		d := new(Desc_4D)
+		assert.NoError(d.Decode(desc))
+		assert.Equal("rus", d.Language)
+		assert.Equal("нет", d.EventName)
+		assert.Equal("ABC", d.Text)
+
+		// UTF-8 encoded
+		decoded := new(Desc_4D)
+		assert.NoError(decoded.Decode(d.Encode()))
+		assert.Equal(d, decoded)
+
+		assert.ErrorIs(d.Decode(desc[:10]), ErrDescriptorFormat)
+	})
+
+	t.Run("extended_event", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := &Desc_4E{
+			Number:     1,
+			LastNumber: 2,
+			Language:   "eng",
+			Items: []Desc_4E_Item{
+				{Description: "Director", Item: "Somebody"},
+				{Description: "Year", Item: "2024"},
+			},
+			Text: "Description",
+		}
+
+		desc := d.Encode()
+		assert.Equal(byte(0x12), desc[2])
+		assert.Equal(byte(len(desc)-2), desc[1])
+
+		decoded := new(Desc_4E)
+		assert.NoError(decoded.Decode(desc))
+		assert.Equal(d, decoded)
+	})
+
+	t.Run("content", func(t *testing.T) {
+		assert := assert.New(t)
+
+		desc := Descriptors{0x54, 0x04, 0x12, 0x00, 0x41, 0xFF}
+
+		d := new(Desc_54)
+		assert.NoError(d.Decode(desc))
+		assert.Equal([]Desc_54_Item{
+			{Level1: 1, Level2: 2, User: 0},
+			{Level1: 4, Level2: 1, User: 0xFF},
+		}, d.Items)
+		assert.Equal(desc, d.Encode())
+	})
+
+	t.Run("parental_rating", func(t *testing.T) {
+		assert := assert.New(t)
+
+		desc := Descriptors{0x55, 0x04, 0x47, 0x42, 0x52, 0x09}
+
+		d := new(Desc_55)
+		assert.NoError(d.Decode(desc))
+		if assert.Equal(1, len(d.Items)) {
+			assert.Equal("GBR", d.Items[0].Country)
+			assert.Equal(12, d.Items[0].MinimumAge())
+		}
+		assert.Equal(desc, d.Encode())
+	})
+
+	t.Run("text limit", func(t *testing.T) {
+		assert := assert.New(t)
+
+		// ISO/IEC 8859-5 text with character table selector
+		long := strings.Repeat("я", 200)
+
+		short := &Desc_4D{Language: "rus", EventName: long, Text: long}
+		desc := short.Encode()
+		assert.Equal(257, len(desc))
+
+		decoded := new(Desc_4D)
+		if assert.NoError(decoded.Decode(desc)) {
+			assert.Equal(long, decoded.EventName)
+			assert.True(strings.HasPrefix(long, decoded.Text))
+		}
+
+		extended := &Desc_4E{
+			Language: "eng",
+			Items: []Desc_4E_Item{
+				{Description: "Director", Item: "Somebody"},
+				{Description: "Cast", Item: strings.Repeat("a", 250)},
+			},
+			Text: strings.Repeat("b", 300),
+		}
+		desc = extended.Encode()
+		assert.Equal(257, len(desc))
+
+		decoded4E := new(Desc_4E)
+		if assert.NoError(decoded4E.Decode(desc)) {
+			assert.Equal(extended.Items[:1], decoded4E.Items)
+			assert.Equal(strings.Repeat("b", 255-6-18), decoded4E.Text)
+		}
+	})
+}
+
+func TestEIT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	item := NewEitItem()
+	item.SetRunningStatus(4)
+	assert.NoError(item.AppendDescriptors((&Desc_4D{Language: "eng", EventName: "Old"}).Encode()))
+
+	name := (&Desc_4D{Language: "eng", EventName: "New"}).Encode()
+	assert.NoError(item.SetDescriptors(item.Descriptors().Replace(name)))
+	assert.Equal(name, item.Descriptors())
+	assert.Equal(uint8(4), item.RunningStatus())
+
+	large := make(Descriptors, EitMaximumSize)
+	assert.ErrorIs(item.AppendDescriptors(large), ErrDescriptorSize)
+	assert.Error(item.SetDescriptors(Descriptors{0x4D, 0x10}))
+}
@@ -2,7 +2,10 @@ module github.com/cesbo/go-mpegts
 
 go 1.19
 
-require github.com/stretchr/testify v1.7.0
+require (
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/text v0.14.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -0,0 +1,81 @@
+package mpegts
+
+import (
+	"time"
+)
+
+const (
+	// Modified Julian Date of the Unix epoch: 1970-01-01
+	mjdUnixEpoch = 40587
+)
+
+// decodeMJD returns UTC time from the 40-bit field:
+// 16-bit Modified Julian Date and 24-bit BCD time.
+// Returns zero time if all bits are set to 1 (undefined time).
+func decodeMJD(b []byte) time.Time {
+	_ = b[4]
+
+	if b[0] == 0xFF && b[1] == 0xFF && b[2] == 0xFF && b[3] == 0xFF && b[4] == 0xFF {
+		return time.Time{}
+	}
+
+	mjd := int64(b[0])<<8 | int64(b[1])
+	sec := (mjd - mjdUnixEpoch) * 86400
+	sec += int64(bcdDecode(uint32(b[2]))) * 3600
+	sec += int64(bcdDecode(uint32(b[3]))) * 60
+	sec += int64(bcdDecode(uint32(b[4])))
+
+	return time.Unix(sec, 0).UTC()
+}
+
+// encodeMJD puts time into the 40-bit MJD+BCD field.
+// Zero time sets all bits to 1.
+func encodeMJD(b []byte, t time.Time) {
+	_ = b[4]
+
+	if t.IsZero() {
+		b[0], b[1], b[2], b[3], b[4] = 0xFF, 0xFF, 0xFF, 0xFF, 0xFF
+		return
+	}
+
+	sec := t.Unix()
+	days := sec / 86400
+	sec -= days * 86400
+	if sec < 0 {
+		days -= 1
+		sec += 86400
+	}
+
+	mjd := uint16(days + mjdUnixEpoch)
+	b[0] = byte(mjd >> 8)
+	b[1] = byte(mjd)
+	b[2] = byte(bcdEncode(uint32(sec / 3600)))
+	b[3] = byte(bcdEncode(uint32((sec / 60) % 60)))
+	b[4] = byte(bcdEncode(uint32(sec % 60)))
+}
+
+// decodeBCDDuration returns duration from the 24-bit BCD field: hh:mm:ss
+func decodeBCDDuration(b []byte) time.Duration {
+	_ = b[2]
+
+	return time.Duration(bcdDecode(uint32(b[0])))*time.Hour +
+		time.Duration(bcdDecode(uint32(b[1])))*time.Minute +
+		time.Duration(bcdDecode(uint32(b[2])))*time.Second
+}
+
+// encodeBCDDuration puts duration into the 24-bit BCD field: hh:mm:ss.
+// Duration is limited to 99:59:59
+func encodeBCDDuration(b []byte, d time.Duration) {
+	_ = b[2]
+
+	sec := int64(d / time.Second)
+	if sec < 0 {
+		sec = 0
+	} else if sec > (99*3600 + 59*60 + 59) {
+		sec = 99*3600 + 59*60 + 59
+	}
+
+	b[0] = byte(bcdEncode(uint32(sec / 3600)))
+	b[1] = byte(bcdEncode(uint32((sec / 60) % 60)))
+	b[2] = byte(bcdEncode(uint32(sec % 60)))
+}
@@ -0,0 +1,111 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	NitHeaderSize  = transportHeaderSize
+	NitMaximumSize = transportMaximumSize
+	NitItemSize    = transportItemSize
+
+	// transport_stream_loop_length field size
+	nitLoopSize = transportLoopSize
+)
+
+// NIT is Network Information Table
+type NIT struct {
+	transportTable
+	Items []*NitItem
+}
+
+// NIT Item contains information about transport stream
+type NitItem struct {
+	transportItem
+}
+
+var (
+	ErrNitFormat = errors.New("nit: invalid format")
+)
+
+var (
+	emptyNit = []byte{
+		0x40,        // table_id
+		0xF0,        // section_length 1
+		0x00,        // section_length 2
+		0x00,        // network_id 1
+		0x00,        // network_id 2
+		0xC0 | 0x01, // version
+		0x00,        // section_number
+		0x00,        // last_section_number
+		0xF0,        // network_descriptors_length 1
+		0x00,        // network_descriptors_length 2
+	}
+)
+
+func NewNit() *NIT {
+	n := new(NIT)
+	n.header = make([]byte, len(emptyNit))
+	copy(n.header, emptyNit)
+
+	return n
+}
+
+func (n *NIT) ParseNitSection(b []byte) error {
+	return n.parse(b, ErrNitFormat, "nit", func(item transportItem) {
+		n.Items = append(n.Items, &NitItem{item})
+	})
+}
+
+// Returns true for NIT of the actual network
+func (n *NIT) Actual() bool {
+	return n.header[0] == 0x40
+}
+
+// SetActual sets table_id: 0x40 for actual network, 0x41 for other network
+func (n *NIT) SetActual(actual bool) {
+	if actual {
+		n.header[0] = 0x40
+	} else {
+		n.header[0] = 0x41
+	}
+}
+
+func (n *NIT) NetworkID() uint16 {
+	return binary.BigEndian.Uint16(n.header[3:])
+}
+
+func (n *NIT) SetNetworkID(id uint16) {
+	binary.BigEndian.PutUint16(n.header[3:], id)
+}
+
+// Calculates LastSectionNumber
+func (n *NIT) Finalize() {
+	n.finalize(n.itemHeader)
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from NIT
+func (n *NIT) Packetizer() *PsiPacketizer {
+	return newPsiPacketizer(n)
+}
+
+func (n *NIT) itemHeader(i int) []byte {
+	if i < len(n.Items) {
+		return n.Items[i].header
+	}
+
+	return nil
+}
+
+func (n *NIT) sectionSize(i int) int {
+	return n.size(i, n.itemHeader)
+}
+
+func (n *NIT) sectionItem(i int) []byte {
+	return n.item(i, n.itemHeader)
+}
+
+func NewNitItem() *NitItem {
+	return &NitItem{newTransportItem()}
+}
@@ -0,0 +1,289 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTestNit() *NIT {
+	nit := NewNit()
+	nit.SetVersion(3)
+	nit.SetNetworkID(1)
+	nit.AppendDescriptors((&Desc_40{NetworkName: "Test"}).Encode())
+
+	item := NewNitItem()
+	item.SetTSID(1001)
+	item.SetONID(1)
+	item.AppendDescriptors((&Desc_41{
+		Services: []Desc_41_Service{
+			{ServiceID: 101, ServiceType: 0x01},
+			{ServiceID: 102, ServiceType: 0x02},
+		},
+	}).Encode())
+	item.AppendDescriptors((&Desc_43{
+		Frequency:       1172743,
+		OrbitalPosition: 192,
+		East:            true,
+		Polarization:    1,
+		RollOff:         0,
+		S2:              true,
+		ModulationType:  2,
+		SymbolRate:      275000,
+		FECInner:        3,
+	}).Encode())
+	nit.Items = append(nit.Items, item)
+
+	return nit
+}
+
+func TestNIT_Packetize(t *testing.T) {
+	assert := assert.New(t)
+
+	nit := makeTestNit()
+	nit.Finalize()
+
+	expected := []byte{
+		0x40, 0xF0, 0x2E, 0x00, 0x01, 0xC7, 0x00, 0x00,
+		0xF0, 0x06, 0x40, 0x04, 0x54, 0x65, 0x73, 0x74,
+		0xF0, 0x1B, 0x03, 0xE9, 0x00, 0x01, 0xF0, 0x15,
+		0x41, 0x06, 0x00, 0x65, 0x01, 0x00, 0x66, 0x02,
+		0x43, 0x0B, 0x01, 0x17, 0x27, 0x43, 0x01, 0x92,
+		0xA6, 0x02, 0x75, 0x00, 0x03,
+	}
+
+	counter := 0
+	ts := NewTS(16)
+	for p := nit.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		assert.Equal(expected, []byte(ts[5:5+len(expected)]))
+		counter += 1
+	}
+	assert.Equal(1, counter)
+
+	// decode packetized section
+	psi := new(PSI)
+	psi.Assemble(ts, func(err error) {
+		if !assert.NoError(err) {
+			return
+		}
+
+		decoded := new(NIT)
+		if !assert.NoError(decoded.ParseNitSection(psi.Payload())) {
+			return
+		}
+
+		assert.True(decoded.Actual())
+		assert.Equal(uint8(3), decoded.Version())
+		assert.Equal(uint16(1), decoded.NetworkID())
+
+		name := new(Desc_40)
+		assert.NoError(name.Decode(decoded.Descriptors()))
+		assert.Equal("Test", name.NetworkName)
+
+		if !assert.Equal(1, len(decoded.Items)) {
+			return
+		}
+
+		item := decoded.Items[0]
+		assert.Equal(uint16(1001), item.TSID())
+		assert.Equal(uint16(1), item.ONID())
+
+		desc := item.Descriptors()
+		services := new(Desc_41)
+		assert.NoError(services.Decode(desc))
+		assert.Equal(2, len(services.Services))
+		assert.Equal(uint16(102), services.Services[1].ServiceID)
+
+		sat := new(Desc_43)
+		assert.NoError(sat.Decode(desc.Next()))
+		assert.Equal(nit.Items[0].Descriptors().Next(), sat.Encode())
+		assert.Equal(uint32(1172743), sat.Frequency)
+		assert.Equal(uint32(275000), sat.SymbolRate)
+		assert.Equal(uint16(192), sat.OrbitalPosition)
+		assert.True(sat.East)
+	})
+}
+
+func TestNIT_MultiSection(t *testing.T) {
+	assert := assert.New(t)
+
+	nit := NewNit()
+	nit.AppendDescriptors((&Desc_40{NetworkName: "Network"}).Encode())
+	for i := 0; i < 100; i++ {
+		item := NewNitItem()
+		item.SetTSID(uint16(i))
+		item.AppendDescriptors((&Desc_44{
+			Frequency:  3460000,
+			FECOuter:   2,
+			Modulation: 3,
+			SymbolRate: 69000,
+		}).Encode())
+		nit.Items = append(nit.Items, item)
+	}
+	nit.Finalize()
+
+	psi := new(PSI)
+	decoded := new(NIT)
+	sections := 0
+
+	ts := NewTS(16)
+	for p := nit.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(uint8(1), psi.LastSectionNumber)
+			assert.NoError(decoded.ParseNitSection(psi.Payload()))
+			sections += 1
+		})
+	}
+
+	assert.Equal(2, sections)
+	assert.Equal(nit.Descriptors(), decoded.Descriptors())
+	if assert.Equal(100, len(decoded.Items)) {
+		cable := new(Desc_44)
+		assert.NoError(cable.Decode(decoded.Items[99].Descriptors()))
+		assert.Equal(uint16(99), decoded.Items[99].TSID())
+		assert.Equal(uint32(3460000), cable.Frequency)
+		assert.Equal(uint32(69000), cable.SymbolRate)
+		assert.Equal(uint8(3), cable.Modulation)
+	}
+}
+
+func TestNIT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	nit := makeTestNit()
+	name := (&Desc_40{NetworkName: "New Name"}).Encode()
+	assert.NoError(nit.SetDescriptors(nit.Descriptors().Replace(name)))
+	assert.Equal(name, nit.Descriptors())
+
+	item := nit.Items[0]
+	assert.NoError(item.SetDescriptors(item.Descriptors().Remove(0x41)))
+	assert.Nil(item.Descriptors().Find(0x41))
+	assert.NotNil(item.Descriptors().Find(0x43))
+
+	nit.Finalize()
+
+	psi := new(PSI)
+	decoded := new(NIT)
+	ts := NewTS(16)
+	for p := nit.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if assert.NoError(err) {
+				assert.NoError(decoded.ParseNitSection(psi.Payload()))
+			}
+		})
+	}
+
+	assert.Equal(name, decoded.Descriptors())
+	if assert.Equal(1, len(decoded.Items)) {
+		assert.Equal(item.Descriptors(), decoded.Items[0].Descriptors())
+	}
+
+	large := make(Descriptors, NitMaximumSize)
+	assert.ErrorIs(nit.AppendDescriptors(large), ErrDescriptorSize)
+	assert.ErrorIs(item.AppendDescriptors(large), ErrDescriptorSize)
+	assert.Error(nit.SetDescriptors(Descriptors{0x40, 0x10}))
+}
+
+func TestNIT_DeliveryDescriptors(t *testing.T) {
+	t.Run("terrestrial", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := Desc_5A{
+			CentreFrequency:  47400000,
+			Bandwidth:        0,
+			Priority:         true,
+			TimeSlicing:      true,
+			MPEFEC:           true,
+			Constellation:    2,
+			CodeRateHP:       2,
+			GuardInterval:    2,
+			TransmissionMode: 1,
+		}
+
+		desc := d.Encode()
+		assert.Equal(Descriptors{
+			0x5A, 0x0B, 0x02, 0xD3, 0x44, 0x40, 0x1F, 0x82,
+			0x12, 0xFF, 0xFF, 0xFF, 0xFF,
+		}, desc)
+
+		decoded := Desc_5A{}
+		assert.NoError(decoded.Decode(desc))
+		assert.Equal(d, decoded)
+	})
+
+	t.Run("S2", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := Desc_79{
+			HasScramblingSequence: true,
+			ScramblingSequence:    0x20001,
+			HasInputStream:        true,
+			InputStreamID:         5,
+		}
+
+		desc := d.Encode()
+		assert.Equal(Descriptors{0x79, 0x05, 0xDF, 0xFE, 0x00, 0x01, 0x05}, desc)
+
+		decoded := Desc_79{}
+		assert.NoError(decoded.Decode(desc))
+		assert.Equal(d, decoded)
+	})
+
+	t.Run("T2", func(t *testing.T) {
+		assert := assert.New(t)
+
+		d := Desc_7F_04{
+			PLPID:            1,
+			T2SystemID:       0x8001,
+			HasDetails:       true,
+			Bandwidth:        0,
+			GuardInterval:    4,
+			TransmissionMode: 3,
+			TFS:              true,
+			Cells: []Desc_7F_04_Cell{
+				{
+					CellID:      1,
+					Frequencies: []uint32{47400000, 48200000},
+					Subcells: []Desc_7F_04_Subcell{
+						{CellIDExtension: 1, TransposerFrequency: 49000000},
+					},
+				},
+			},
+		}
+
+		desc := d.Encode()
+		assert.Equal(byte(len(desc)-2), desc[1])
+
+		decoded := Desc_7F_04{}
+		assert.NoError(decoded.Decode(desc))
+		assert.Equal(d, decoded)
+
+		// short form
+		d = Desc_7F_04{PLPID: 2, T2SystemID: 3}
+		desc = d.Encode()
+		assert.Equal(Descriptors{0x7F, 0x04, 0x04, 0x02, 0x00, 0x03}, desc)
+		assert.NoError(decoded.Decode(desc))
+		assert.Equal(d, decoded)
+
+		// truncated cell
+		desc = Descriptors{0x7F, 0x08, 0x04, 0x02, 0x00, 0x03, 0x03, 0x01, 0x00, 0x01}
+		assert.ErrorIs(decoded.Decode(desc), ErrDescriptorFormat)
+	})
+}
+
+func TestDesc_40_Text(t *testing.T) {
+	assert := assert.New(t)
+
+	// ISO/IEC 8859-5
+	desc := Descriptors{0x40, 0x04, 0x01, 0xC0, 0xC2, 0xC1}
+
+	d := new(Desc_40)
+	if assert.NoError(d.Decode(desc)) {
+		assert.Equal("РТС", d.NetworkName)
+	}
+
+	assert.Equal(desc, d.Encode())
+}
@@ -60,62 +60,350 @@ func (p PES) SetLength(value int) {
 	binary.BigEndian.PutUint16(p[4:], uint16(value))
 }
 
+// Length returns PES_packet_length field.
+// Returns 0 for unbounded PES or if buffer is too short.
+func (p PES) Length() int {
+	if len(p) < PesHeaderSize {
+		return 0
+	}
+
+	return int(binary.BigEndian.Uint16(p[4:]))
+}
+
+// hasOptionalHeader checks is PES has optional header with flags
+// and PES_header_data_length
+func (p PES) hasOptionalHeader() bool {
+	return len(p) >= (PesHeaderSize+PesOptionalHeaderSize) && p.IsES()
+}
+
+// HeaderLength returns PES_header_data_length field.
+// Number of bytes of optional fields and stuffing bytes.
+// Returns 0 if PES has no optional header.
+func (p PES) HeaderLength() int {
+	if !p.hasOptionalHeader() {
+		return 0
+	}
+
+	return int(p[8])
+}
+
+// headerEnd returns position of the first byte after optional fields
+// limited by buffer length
+func (p PES) headerEnd() int {
+	end := PesHeaderSize + PesOptionalHeaderSize + p.HeaderLength()
+	if end > len(p) {
+		return len(p)
+	}
+
+	return end
+}
+
+// Payload returns PES packet data bytes after header.
+// Returns nil if header is incomplete.
+func (p PES) Payload() []byte {
+	if len(p) < PesHeaderSize || !p.CheckPrefix() {
+		return nil
+	}
+
+	skip := PesHeaderSize
+	if p.IsES() {
+		if len(p) < (PesHeaderSize + PesOptionalHeaderSize) {
+			return nil
+		}
+
+		skip += PesOptionalHeaderSize + int(p[8])
+		if skip > len(p) {
+			return nil
+		}
+	}
+
+	end := len(p)
+	if length := p.Length(); length != 0 && (PesHeaderSize+length) < end {
+		end = PesHeaderSize + length
+	}
+
+	return p[skip:end]
+}
+
+// flag checks bit in the optional header flags: 6 - first byte, 7 - second byte
+func (p PES) flag(i int, mask byte) bool {
+	return p.hasOptionalHeader() && (p[i]&mask) != 0
+}
+
+func (p PES) setFlag(i int, mask byte, value bool) {
+	if value {
+		p[i] |= mask
+	} else {
+		p[i] &^= mask
+	}
+}
+
+// ScramblingControl returns PES_scrambling_control field
+func (p PES) ScramblingControl() ScramblingControl {
+	if !p.hasOptionalHeader() {
+		return NotScrambled
+	}
+
+	return ScramblingControl((p[6] & 0x30) >> 4)
+}
+
+// SetScramblingControl sets PES_scrambling_control field
+func (p PES) SetScramblingControl(value ScramblingControl) {
+	p[6] = (p[6] &^ 0x30) | ((byte(value) << 4) & 0x30)
+}
+
+// HasPriority checks PES_priority flag
+func (p PES) HasPriority() bool {
+	return p.flag(6, 0x08)
+}
+
+// SetPriority sets PES_priority flag
+func (p PES) SetPriority(value bool) {
+	p.setFlag(6, 0x08, value)
+}
+
+// IsDataAligned checks data_alignment_indicator.
+// If true PES header is immediately followed by the video syntax element
+// or audio sync word.
+func (p PES) IsDataAligned() bool {
+	return p.flag(6, 0x04)
+}
+
+// SetDataAlignment sets data_alignment_indicator
+func (p PES) SetDataAlignment(value bool) {
+	p.setFlag(6, 0x04, value)
+}
+
+// HasCopyright checks copyright flag
+func (p PES) HasCopyright() bool {
+	return p.flag(6, 0x02)
+}
+
+// SetCopyright sets copyright flag
+func (p PES) SetCopyright(value bool) {
+	p.setFlag(6, 0x02, value)
+}
+
+// IsOriginal checks original_or_copy flag
+func (p PES) IsOriginal() bool {
+	return p.flag(6, 0x01)
+}
+
+// SetOriginal sets original_or_copy flag
+func (p PES) SetOriginal(value bool) {
+	p.setFlag(6, 0x01, value)
+}
+
+// Optional fields flags
+const (
+	pesFlagPTS            = 0x80
+	pesFlagDTS            = 0x40
+	pesFlagESCR           = 0x20
+	pesFlagESRate         = 0x10
+	pesFlagTrickMode      = 0x08
+	pesFlagAdditionalCopy = 0x04
+	pesFlagCRC            = 0x02
+	pesFlagExtension      = 0x01
+)
+
+// optionalField returns offset of the optional field and checks that
+// size bytes of the field are available.
+// Returns -1 if field not defined or out of range.
+func (p PES) optionalField(flag byte, size int) int {
+	if !p.hasOptionalHeader() {
+		return -1
+	}
+
+	flags := p[7]
+	if (flags & flag) == 0 {
+		return -1
+	}
+
+	skip := PesHeaderSize + PesOptionalHeaderSize
+
+	fields := [...]struct {
+		flag byte
+		size int
+	}{
+		{pesFlagPTS, 5},
+		{pesFlagDTS, 5},
+		{pesFlagESCR, 6},
+		{pesFlagESRate, 3},
+		{pesFlagTrickMode, 1},
+		{pesFlagAdditionalCopy, 1},
+		{pesFlagCRC, 2},
+	}
+
+	for _, f := range fields {
+		if f.flag == flag {
+			break
+		}
+		if (flags & f.flag) != 0 {
+			skip += f.size
+		}
+	}
+
+	if (skip + size) > p.headerEnd() {
+		return -1
+	}
+
+	return skip
+}
+
 // HasPTS checks is a Presentation Time Stamp (PTS) defined in the PES header.
 // PTS field presents only for elementary streams.
 func (p PES) HasPTS() bool {
-	return (p[7] & 0x80) != 0
+	return p.flag(7, pesFlagPTS)
 }
 
 // PTS returns PTS value.
+// Returns NonTimestamp if PTS is not defined or out of range.
 func (p PES) PTS() Timestamp {
-	return (Timestamp(p[9]&0x0E) << 29) |
-		(Timestamp(p[10]) << 22) |
-		(Timestamp(p[11]&0xFE) << 14) |
-		(Timestamp(p[12]) << 7) |
-		(Timestamp(p[13]) >> 1)
+	skip := p.optionalField(pesFlagPTS, 5)
+	if skip == -1 {
+		return NonTimestamp
+	}
+
+	return getTimestamp(p[skip:])
 }
 
 // SetPTS sets PTS value and turn on PTS flag.
 func (p PES) SetPTS(value Timestamp) {
-	_ = p[13]
-	value &= MaxTimestamp
-
 	p[7] |= 0x80
-
-	p[9] = 0x20 | byte(value>>29) | 0x01
-	p[10] = byte(value >> 22)
-	p[11] = byte(value>>14) | 0x01
-	p[12] = byte(value >> 7)
-	p[13] = byte(value<<1) | 0x01
+	setTimestamp(p[9:], 0x20, value)
 }
 
 // HasDTS checks a Decoding TimeStamp (DTS) is defined in the PES header.
 // DTS field presents only in pair with PTS field.
 // If DTS field is not presented than DTS value equal to PTS.
 func (p PES) HasDTS() bool {
-	return (p[7] & 0x40) != 0
+	return p.flag(7, pesFlagDTS)
 }
 
 // DTS returns DTS value.
+// Returns NonTimestamp if DTS is not defined or out of range.
 func (p PES) DTS() Timestamp {
-	return (Timestamp(p[14]&0x0E) << 29) |
-		(Timestamp(p[15]) << 22) |
-		(Timestamp(p[16]&0xFE) << 14) |
-		(Timestamp(p[17]) << 7) |
-		(Timestamp(p[18]) >> 1)
+	if !p.HasPTS() {
+		return NonTimestamp
+	}
+
+	skip := p.optionalField(pesFlagDTS, 5)
+	if skip == -1 {
+		return NonTimestamp
+	}
+
+	return getTimestamp(p[skip:])
 }
 
 // SetDTS sets DTS value and turn on DTS flag.
 func (p PES) SetDTS(value Timestamp) {
-	_ = p[18]
-	value &= MaxTimestamp
-
 	p[7] |= 0x40
 	p[9] |= 0x10
+	setTimestamp(p[14:], 0x10, value)
+}
+
+// HasESCR checks is an Elementary Stream Clock Reference defined in the PES header
+func (p PES) HasESCR() bool {
+	return p.flag(7, pesFlagESCR)
+}
+
+// ESCR returns ESCR value in 27MHz units.
+// Returns NonPcr if ESCR is not defined or out of range.
+func (p PES) ESCR() PCR {
+	skip := p.optionalField(pesFlagESCR, 6)
+	if skip == -1 {
+		return NonPcr
+	}
+
+	return getESCR(p[skip:])
+}
+
+// HasESRate checks is ES_rate defined in the PES header
+func (p PES) HasESRate() bool {
+	return p.flag(7, pesFlagESRate)
+}
+
+// ESRate returns ES_rate in units of 50 bytes/second.
+// Returns 0 if ES_rate is not defined or out of range.
+func (p PES) ESRate() uint32 {
+	skip := p.optionalField(pesFlagESRate, 3)
+	if skip == -1 {
+		return 0
+	}
+
+	return getESRate(p[skip:])
+}
+
+// HasTrickMode checks is DSM_trick_mode defined in the PES header
+func (p PES) HasTrickMode() bool {
+	return p.flag(7, pesFlagTrickMode)
+}
+
+// TrickMode returns 8-bit DSM trick mode field:
+// 3-bit trick_mode_control and 5 bits of the mode parameters.
+// Returns 0 if trick mode is not defined or out of range.
+func (p PES) TrickMode() uint8 {
+	skip := p.optionalField(pesFlagTrickMode, 1)
+	if skip == -1 {
+		return 0
+	}
+
+	return p[skip]
+}
+
+// HasAdditionalCopyInfo checks is additional_copy_info defined in the PES header
+func (p PES) HasAdditionalCopyInfo() bool {
+	return p.flag(7, pesFlagAdditionalCopy)
+}
+
+// AdditionalCopyInfo returns 7-bit additional_copy_info.
+// Returns 0 if field is not defined or out of range.
+func (p PES) AdditionalCopyInfo() uint8 {
+	skip := p.optionalField(pesFlagAdditionalCopy, 1)
+	if skip == -1 {
+		return 0
+	}
+
+	return p[skip] & 0x7F
+}
+
+// HasPreviousCRC checks is previous_PES_packet_CRC defined in the PES header
+func (p PES) HasPreviousCRC() bool {
+	return p.flag(7, pesFlagCRC)
+}
+
+// PreviousCRC returns CRC of the previous PES packet data bytes.
+// Returns 0 if field is not defined or out of range.
+func (p PES) PreviousCRC() uint16 {
+	skip := p.optionalField(pesFlagCRC, 2)
+	if skip == -1 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint16(p[skip:])
+}
+
+// HasExtension checks is PES_extension defined in the PES header
+func (p PES) HasExtension() bool {
+	return p.flag(7, pesFlagExtension)
+}
+
+// Extension returns parsed PES_extension.
+// Returns nil if extension is not defined.
+func (p PES) Extension() (*PESExtension, error) {
+	if !p.HasExtension() {
+		return nil, nil
+	}
+
+	skip := p.optionalField(pesFlagExtension, 1)
+	if skip == -1 {
+		return nil, ErrPesHeader
+	}
+
+	e := new(PESExtension)
+	if _, err := e.decode(p[skip:p.headerEnd()]); err != nil {
+		return nil, err
+	}
 
-	p[14] = 0x10 | byte(value>>29) | 0x01
-	p[15] = byte(value >> 22)
-	p[16] = byte(value>>14) | 0x01
-	p[17] = byte(value >> 7)
-	p[18] = byte(value<<1) | 0x01
+	return e, nil
 }
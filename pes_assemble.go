@@ -0,0 +1,189 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// PES header size: packet_start_code_prefix, stream_id, PES_packet_length
+	PesHeaderSize = 6
+
+	// Optional PES header size: flags and PES_header_data_length
+	PesOptionalHeaderSize = 3
+)
+
+// PESAssembler assembles TS packets into PES packets.
+// Supports bounded PES and unbounded PES (PES_packet_length is 0)
+// used for video elementary streams.
+// Unbounded PES completes on the next packet with payload start.
+type PESAssembler struct {
+	cc      uint8
+	started bool
+
+	buffer []byte // PES buffer
+	size   int    // expected PES size or 0 for unbounded PES
+}
+
+var (
+	ErrPesCC     = errors.New("pes: discontinuity received")
+	ErrPesTEI    = errors.New("pes: transport error indicator")
+	ErrPesHeader = errors.New("pes: invalid header")
+	ErrPesLength = errors.New("pes: length mismatch")
+)
+
+// Clears buffer
+func (a *PESAssembler) Clear() {
+	a.started = false
+	a.buffer = a.buffer[:0]
+	a.size = 0
+}
+
+// Returns assembled PES. Should be used in AssembleFn.
+// PES is valid till AssembleFn returns.
+func (a *PESAssembler) PES() PES {
+	return PES(a.buffer)
+}
+
+// checkHeader validates PES header
+func (a *PESAssembler) checkHeader() error {
+	pes := a.PES()
+
+	if len(pes) < PesHeaderSize || !pes.CheckPrefix() {
+		return ErrPesHeader
+	}
+
+	if !pes.IsES() {
+		return nil
+	}
+
+	if len(pes) < (PesHeaderSize + PesOptionalHeaderSize) {
+		return ErrPesHeader
+	}
+
+	if len(pes) < (PesHeaderSize + PesOptionalHeaderSize + int(pes[8])) {
+		return ErrPesHeader
+	}
+
+	if pes.HasDTS() && !pes.HasPTS() {
+		return ErrPesHeader
+	}
+
+	return nil
+}
+
+func (a *PESAssembler) callAssembleFn(fn AssembleFn, err error) {
+	if err == nil {
+		err = a.checkHeader()
+	}
+
+	fn(err)
+
+	a.Clear()
+}
+
+// append adds payload to the buffer.
+// Returns true if bounded PES is completed.
+func (a *PESAssembler) append(payload []byte) (bool, error) {
+	a.buffer = append(a.buffer, payload...)
+
+	if a.size == 0 {
+		if len(a.buffer) < PesHeaderSize {
+			return false, nil
+		}
+
+		length := int(binary.BigEndian.Uint16(a.buffer[4:]))
+		if length == 0 {
+			// unbounded PES
+			return false, nil
+		}
+
+		a.size = PesHeaderSize + length
+	}
+
+	switch {
+	case len(a.buffer) < a.size:
+		return false, nil
+	case len(a.buffer) == a.size:
+		return true, nil
+	default:
+		// stuffing after bounded PES is allowed only in the last packet.
+		// drop it if it is 0xFF
+		for _, b := range a.buffer[a.size:] {
+			if b != 0xFF {
+				return false, ErrPesLength
+			}
+		}
+		a.buffer = a.buffer[:a.size]
+		return true, nil
+	}
+}
+
+// Flush completes unbounded PES. Should be called on the end of stream.
+func (a *PESAssembler) Flush(fn AssembleFn) {
+	if !a.started {
+		return
+	}
+
+	if a.size != 0 {
+		a.callAssembleFn(fn, ErrPesLength)
+	} else {
+		a.callAssembleFn(fn, nil)
+	}
+}
+
+// Assembles TS packets into single PES.
+// Calls fn when PES is ready or error occurs
+func (a *PESAssembler) Assemble(packet TS, fn AssembleFn) {
+	if packet.HasTEI() {
+		if a.started {
+			a.callAssembleFn(fn, ErrPesTEI)
+		}
+		return
+	}
+
+	payload := packet.Payload()
+	if payload == nil {
+		return
+	}
+
+	cc, ok := packet.CheckCC(a.cc)
+	if packet.HasDiscontinuity() {
+		// continuity counter may be discontinuous in this packet
+		ok = true
+	} else if a.started && cc == a.cc {
+		// duplicate packet
+		return
+	}
+
+	if packet.HasPUSI() {
+		if a.started && !ok {
+			// previous PES is not complete
+			a.callAssembleFn(fn, ErrPesCC)
+		} else {
+			// previous PES completed by the payload start
+			a.Flush(fn)
+		}
+
+		a.started = true
+		a.cc = cc
+	} else {
+		if !a.started {
+			return
+		}
+
+		if !ok {
+			a.callAssembleFn(fn, ErrPesCC)
+			return
+		}
+
+		a.cc = cc
+	}
+
+	done, err := a.append(payload)
+	if err != nil {
+		a.callAssembleFn(fn, err)
+	} else if done {
+		a.callAssembleFn(fn, nil)
+	}
+}
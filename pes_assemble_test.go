@@ -0,0 +1,213 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// makePesPackets splits PES into TS packets
+func makePesPackets(pid PID, pes []byte, cc uint8) []TS {
+	var result []TS
+
+	for skip := 0; skip < len(pes); {
+		packet := NewTS(pid)
+		packet.SetPayload()
+		packet.SetCC(cc)
+		cc += 1
+
+		if skip == 0 {
+			packet.SetPUSI()
+		}
+
+		n := copy(packet[4:], pes[skip:])
+		skip += n
+		if n < PacketSize-4 {
+			packet.Fill(4 + n)
+		}
+
+		result = append(result, packet)
+	}
+
+	return result
+}
+
+func makeTestPes(size int, bounded bool) []byte {
+	pes := make([]byte, size)
+	copy(pes, []byte{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0x80,
+		0x05, 0x21, 0x00, 0x01, 0x00, 0x01,
+	})
+
+	for i := 14; i < size; i++ {
+		pes[i] = byte(i)
+	}
+
+	if bounded {
+		PES(pes).SetLength(size - PesHeaderSize)
+	}
+
+	return pes
+}
+
+func TestPESAssembler(t *testing.T) {
+	t.Run("bounded", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes := makeTestPes(500, true)
+		assembler := PESAssembler{}
+		count := 0
+
+		for _, packet := range makePesPackets(256, pes, 0) {
+			assembler.Assemble(packet, func(err error) {
+				assert.NoError(err)
+				assert.Equal(PES(pes), assembler.PES())
+				assert.Equal(Timestamp(0), assembler.PES().PTS())
+				count += 1
+			})
+		}
+
+		assert.Equal(1, count)
+	})
+
+	t.Run("unbounded", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes1 := makeTestPes(500, false)
+		pes2 := makeTestPes(200, false)
+
+		assembler := PESAssembler{}
+		var result []PES
+
+		onPes := func(err error) {
+			assert.NoError(err)
+			p := make(PES, len(assembler.PES()))
+			copy(p, assembler.PES())
+			result = append(result, p)
+		}
+
+		for _, packet := range makePesPackets(256, pes1, 0) {
+			assembler.Assemble(packet, onPes)
+		}
+		assert.Empty(result)
+
+		for _, packet := range makePesPackets(256, pes2, 3) {
+			assembler.Assemble(packet, onPes)
+		}
+		assert.Equal([]PES{pes1}, result)
+
+		assembler.Flush(onPes)
+		assert.Equal([]PES{pes1, pes2}, result)
+	})
+
+	t.Run("discontinuity", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes := makeTestPes(500, true)
+		packets := makePesPackets(256, pes, 0)
+
+		assembler := PESAssembler{}
+		var errors []error
+
+		onPes := func(err error) {
+			errors = append(errors, err)
+		}
+
+		assembler.Assemble(packets[0], onPes)
+		// duplicate packet
+		assembler.Assemble(packets[0], onPes)
+		assembler.Assemble(packets[2], onPes)
+
+		assert.Equal([]error{ErrPesCC}, errors)
+	})
+
+	t.Run("unbounded discontinuity", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes1 := makeTestPes(500, false)
+		pes2 := makeTestPes(100, false)
+
+		assembler := PESAssembler{}
+		var errors []error
+
+		onPes := func(err error) {
+			errors = append(errors, err)
+		}
+
+		for _, packet := range makePesPackets(256, pes1, 0) {
+			assembler.Assemble(packet, onPes)
+		}
+
+		// continuity counter skipped on payload start
+		assembler.Assemble(makePesPackets(256, pes2, 5)[0], onPes)
+		assert.Equal([]error{ErrPesCC}, errors)
+
+		// discontinuity_indicator is set
+		packet := makePesPackets(256, pes2, 10)[0]
+		packet.SetDiscontinuity(true)
+		assembler.Assemble(packet, onPes)
+		assert.Equal([]error{ErrPesCC, nil}, errors)
+
+		assembler.Flush(onPes)
+		assert.Equal([]error{ErrPesCC, nil, nil}, errors)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes := makeTestPes(500, true)
+		packets := makePesPackets(256, pes, 0)
+
+		assembler := PESAssembler{}
+		var errors []error
+
+		onPes := func(err error) {
+			errors = append(errors, err)
+		}
+
+		assembler.Assemble(packets[0], onPes)
+		assembler.Assemble(packets[1], onPes)
+		assembler.Assemble(makePesPackets(256, pes, 2)[0], onPes)
+
+		assert.Equal([]error{ErrPesLength}, errors)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes := makeTestPes(500, true)
+		packets := makePesPackets(256, pes, 0)
+		packets[1][1] |= 0x80
+
+		assembler := PESAssembler{}
+		var errors []error
+
+		onPes := func(err error) {
+			errors = append(errors, err)
+		}
+
+		for _, packet := range packets {
+			assembler.Assemble(packet, onPes)
+		}
+
+		assert.Equal([]error{ErrPesTEI}, errors)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		assert := assert.New(t)
+
+		pes := makeTestPes(100, true)
+		pes[8] = 100 // PES_header_data_length out of range
+
+		assembler := PESAssembler{}
+		var errors []error
+
+		for _, packet := range makePesPackets(256, pes, 0) {
+			assembler.Assemble(packet, func(err error) {
+				errors = append(errors, err)
+			})
+		}
+
+		assert.Equal([]error{ErrPesHeader}, errors)
+	})
+}
@@ -0,0 +1,536 @@
+package mpegts
+
+// PESHeader contains all fields of the PES packet header.
+// ISO/IEC 13818-1 : 2.4.3.7 Semantic definition of fields in PES packet
+type PESHeader struct {
+	StreamID uint8
+
+	// PES_packet_length. 0 for unbounded PES
+	Length int
+
+	ScramblingControl ScramblingControl
+	Priority          bool // PES_priority
+	DataAlignment     bool // data_alignment_indicator
+	Copyright         bool
+	Original          bool // original_or_copy
+
+	HasPTS bool
+	PTS    Timestamp
+
+	HasDTS bool
+	DTS    Timestamp
+
+	HasESCR bool
+	ESCR    PCR
+
+	HasESRate bool
+	ESRate    uint32 // 22-bit rate in units of 50 bytes/second
+
+	HasTrickMode bool
+	TrickMode    uint8 // trick_mode_control and mode parameters
+
+	HasAdditionalCopyInfo bool
+	AdditionalCopyInfo    uint8
+
+	HasPreviousCRC bool
+	PreviousCRC    uint16
+
+	// PES_extension. nil if not present
+	Extension *PESExtension
+
+	// Number of stuffing bytes in the header
+	Stuffing int
+}
+
+// PESExtension contains fields of the PES_extension
+type PESExtension struct {
+	// PES_private_data. 16 bytes or nil if not present
+	PrivateData []byte
+
+	// pack_header. nil if not present
+	PackHeader []byte
+
+	HasSequenceCounter  bool
+	SequenceCounter     uint8 // program_packet_sequence_counter
+	MPEG1               bool  // MPEG1_MPEG2_identifier
+	OriginalStuffLength uint8
+
+	HasPSTDBuffer   bool
+	PSTDBufferScale uint8
+	PSTDBufferSize  uint16
+
+	// PES_extension_field_2 data without length. nil if not present
+	Extension2 []byte
+}
+
+// Trick mode control values
+const (
+	TrickModeFastForward = 0
+	TrickModeSlowMotion  = 1
+	TrickModeFreezeFrame = 2
+	TrickModeFastReverse = 3
+	TrickModeSlowReverse = 4
+)
+
+const (
+	pesExtPrivateData     = 0x80
+	pesExtPackHeader      = 0x40
+	pesExtSequenceCounter = 0x20
+	pesExtPSTDBuffer      = 0x10
+	pesExtExtension2      = 0x01
+	pesExtReserved        = 0x0E
+	pesPrivateDataSize    = 16
+)
+
+// getESCR returns ESCR value from 6 bytes
+func getESCR(b []byte) PCR {
+	_ = b[5]
+	base := (PCR(b[0]&0x38) << 27) |
+		(PCR(b[0]&0x03) << 28) |
+		(PCR(b[1]) << 20) |
+		(PCR(b[2]&0xF8) << 12) |
+		(PCR(b[2]&0x03) << 13) |
+		(PCR(b[3]) << 5) |
+		(PCR(b[4]) >> 3)
+	ext := (PCR(b[4]&0x03) << 7) | (PCR(b[5]) >> 1)
+
+	return (base * 300) + ext
+}
+
+// setESCR puts ESCR value into 6 bytes with marker bits
+func setESCR(b []byte, value PCR) {
+	_ = b[5]
+	base := value / 300
+	ext := value - (base * 300)
+
+	b[0] = 0xC0 | byte((base>>27)&0x38) | 0x04 | byte((base>>28)&0x03)
+	b[1] = byte(base >> 20)
+	b[2] = byte((base>>12)&0xF8) | 0x04 | byte((base>>13)&0x03)
+	b[3] = byte(base >> 5)
+	b[4] = byte((base<<3)&0xF8) | 0x04 | byte((ext>>7)&0x03)
+	b[5] = byte(ext<<1) | 0x01
+}
+
+// getESRate returns 22-bit ES_rate from 3 bytes with marker bits
+func getESRate(b []byte) uint32 {
+	_ = b[2]
+	return ((uint32(b[0]&0x7F) << 15) |
+		(uint32(b[1]) << 7) |
+		(uint32(b[2]) >> 1))
+}
+
+// setESRate puts 22-bit ES_rate into 3 bytes with marker bits
+func setESRate(b []byte, value uint32) {
+	_ = b[2]
+	b[0] = 0x80 | byte((value>>15)&0x7F)
+	b[1] = byte(value >> 7)
+	b[2] = byte(value<<1) | 0x01
+}
+
+// TrickModeControl returns 3-bit trick_mode_control
+func (h *PESHeader) TrickModeControl() uint8 {
+	return h.TrickMode >> 5
+}
+
+// Decode parses PES header.
+// All fields are validated against PES_header_data_length and buffer length.
+func (h *PESHeader) Decode(p PES) error {
+	*h = PESHeader{}
+
+	if len(p) < PesHeaderSize || !p.CheckPrefix() {
+		return ErrPesHeader
+	}
+
+	h.StreamID = p.StreamID()
+	h.Length = p.Length()
+
+	if !p.IsES() {
+		return nil
+	}
+
+	if len(p) < (PesHeaderSize + PesOptionalHeaderSize) {
+		return ErrPesHeader
+	}
+
+	if (p[6] & 0xC0) != 0x80 {
+		return ErrPesHeader
+	}
+
+	end := PesHeaderSize + PesOptionalHeaderSize + int(p[8])
+	if end > len(p) {
+		return ErrPesHeader
+	}
+
+	h.ScramblingControl = p.ScramblingControl()
+	h.Priority = p.HasPriority()
+	h.DataAlignment = p.IsDataAligned()
+	h.Copyright = p.HasCopyright()
+	h.Original = p.IsOriginal()
+
+	flags := p[7]
+	skip := PesHeaderSize + PesOptionalHeaderSize
+
+	field := func(size int) bool {
+		return (skip + size) <= end
+	}
+
+	if (flags & pesFlagPTS) != 0 {
+		if !field(5) {
+			return ErrPesHeader
+		}
+		h.HasPTS = true
+		h.PTS = getTimestamp(p[skip:])
+		skip += 5
+
+		if (flags & pesFlagDTS) != 0 {
+			if !field(5) {
+				return ErrPesHeader
+			}
+			h.HasDTS = true
+			h.DTS = getTimestamp(p[skip:])
+			skip += 5
+		}
+	} else if (flags & pesFlagDTS) != 0 {
+		// forbidden value of PTS_DTS_flags
+		return ErrPesHeader
+	}
+
+	if (flags & pesFlagESCR) != 0 {
+		if !field(6) {
+			return ErrPesHeader
+		}
+		h.HasESCR = true
+		h.ESCR = getESCR(p[skip:])
+		skip += 6
+	}
+
+	if (flags & pesFlagESRate) != 0 {
+		if !field(3) {
+			return ErrPesHeader
+		}
+		h.HasESRate = true
+		h.ESRate = getESRate(p[skip:])
+		skip += 3
+	}
+
+	if (flags & pesFlagTrickMode) != 0 {
+		if !field(1) {
+			return ErrPesHeader
+		}
+		h.HasTrickMode = true
+		h.TrickMode = p[skip]
+		skip += 1
+	}
+
+	if (flags & pesFlagAdditionalCopy) != 0 {
+		if !field(1) {
+			return ErrPesHeader
+		}
+		h.HasAdditionalCopyInfo = true
+		h.AdditionalCopyInfo = p[skip] & 0x7F
+		skip += 1
+	}
+
+	if (flags & pesFlagCRC) != 0 {
+		if !field(2) {
+			return ErrPesHeader
+		}
+		h.HasPreviousCRC = true
+		h.PreviousCRC = uint16(p[skip])<<8 | uint16(p[skip+1])
+		skip += 2
+	}
+
+	if (flags & pesFlagExtension) != 0 {
+		h.Extension = new(PESExtension)
+		n, err := h.Extension.decode(p[skip:end])
+		if err != nil {
+			h.Extension = nil
+			return err
+		}
+		skip += n
+	}
+
+	h.Stuffing = end - skip
+
+	return nil
+}
+
+// decode parses PES_extension. Returns number of bytes
+func (e *PESExtension) decode(b []byte) (int, error) {
+	if len(b) < 1 {
+		return 0, ErrPesHeader
+	}
+
+	flags := b[0]
+	skip := 1
+
+	if (flags & pesExtPrivateData) != 0 {
+		if (skip + pesPrivateDataSize) > len(b) {
+			return 0, ErrPesHeader
+		}
+		e.PrivateData = make([]byte, pesPrivateDataSize)
+		copy(e.PrivateData, b[skip:])
+		skip += pesPrivateDataSize
+	}
+
+	if (flags & pesExtPackHeader) != 0 {
+		if (skip + 1) > len(b) {
+			return 0, ErrPesHeader
+		}
+		next := skip + 1 + int(b[skip])
+		if next > len(b) {
+			return 0, ErrPesHeader
+		}
+		e.PackHeader = make([]byte, next-skip-1)
+		copy(e.PackHeader, b[skip+1:])
+		skip = next
+	}
+
+	if (flags & pesExtSequenceCounter) != 0 {
+		if (skip + 2) > len(b) {
+			return 0, ErrPesHeader
+		}
+		e.HasSequenceCounter = true
+		e.SequenceCounter = b[skip] & 0x7F
+		e.MPEG1 = (b[skip+1] & 0x40) != 0
+		e.OriginalStuffLength = b[skip+1] & 0x3F
+		skip += 2
+	}
+
+	if (flags & pesExtPSTDBuffer) != 0 {
+		if (skip + 2) > len(b) {
+			return 0, ErrPesHeader
+		}
+		e.HasPSTDBuffer = true
+		e.PSTDBufferScale = (b[skip] >> 5) & 0x01
+		e.PSTDBufferSize = (uint16(b[skip]&0x1F) << 8) | uint16(b[skip+1])
+		skip += 2
+	}
+
+	if (flags & pesExtExtension2) != 0 {
+		if (skip + 1) > len(b) {
+			return 0, ErrPesHeader
+		}
+		next := skip + 1 + int(b[skip]&0x7F)
+		if next > len(b) {
+			return 0, ErrPesHeader
+		}
+		e.Extension2 = make([]byte, next-skip-1)
+		copy(e.Extension2, b[skip+1:])
+		skip = next
+	}
+
+	return skip, nil
+}
+
+// StreamIDExtension returns stream_id_extension from the PES_extension_field_2.
+// Returns false if stream_id_extension is not defined.
+func (e *PESExtension) StreamIDExtension() (uint8, bool) {
+	if len(e.Extension2) == 0 || (e.Extension2[0]&0x80) != 0 {
+		return 0, false
+	}
+
+	return e.Extension2[0] & 0x7F, true
+}
+
+func (e *PESExtension) size() int {
+	size := 1
+
+	if e.PrivateData != nil {
+		size += pesPrivateDataSize
+	}
+	if e.PackHeader != nil {
+		size += 1 + len(e.PackHeader)
+	}
+	if e.HasSequenceCounter {
+		size += 2
+	}
+	if e.HasPSTDBuffer {
+		size += 2
+	}
+	if e.Extension2 != nil {
+		size += 1 + len(e.Extension2)
+	}
+
+	return size
+}
+
+func (e *PESExtension) encode(b []byte) error {
+	if e.PrivateData != nil && len(e.PrivateData) != pesPrivateDataSize {
+		return ErrPesHeader
+	}
+	if len(e.PackHeader) > 0xFF || len(e.Extension2) > 0x7F {
+		return ErrPesHeader
+	}
+
+	b[0] = pesExtReserved
+	skip := 1
+
+	if e.PrivateData != nil {
+		b[0] |= pesExtPrivateData
+		skip += copy(b[skip:], e.PrivateData)
+	}
+
+	if e.PackHeader != nil {
+		b[0] |= pesExtPackHeader
+		b[skip] = byte(len(e.PackHeader))
+		skip += 1
+		skip += copy(b[skip:], e.PackHeader)
+	}
+
+	if e.HasSequenceCounter {
+		b[0] |= pesExtSequenceCounter
+		b[skip] = 0x80 | (e.SequenceCounter & 0x7F)
+		b[skip+1] = 0x80 | (e.OriginalStuffLength & 0x3F)
+		if e.MPEG1 {
+			b[skip+1] |= 0x40
+		}
+		skip += 2
+	}
+
+	if e.HasPSTDBuffer {
+		b[0] |= pesExtPSTDBuffer
+		b[skip] = 0x40 | ((e.PSTDBufferScale & 0x01) << 5) | byte((e.PSTDBufferSize>>8)&0x1F)
+		b[skip+1] = byte(e.PSTDBufferSize)
+		skip += 2
+	}
+
+	if e.Extension2 != nil {
+		b[0] |= pesExtExtension2
+		b[skip] = 0x80 | byte(len(e.Extension2))
+		skip += 1
+		copy(b[skip:], e.Extension2)
+	}
+
+	return nil
+}
+
+// Size returns number of bytes required for the PES header
+func (h *PESHeader) Size() int {
+	size := PesHeaderSize
+
+	if !PES([]byte{0, 0, 1, h.StreamID}).IsES() {
+		return size
+	}
+
+	size += PesOptionalHeaderSize
+
+	if h.HasPTS {
+		size += 5
+		if h.HasDTS {
+			size += 5
+		}
+	}
+	if h.HasESCR {
+		size += 6
+	}
+	if h.HasESRate {
+		size += 3
+	}
+	if h.HasTrickMode {
+		size += 1
+	}
+	if h.HasAdditionalCopyInfo {
+		size += 1
+	}
+	if h.HasPreviousCRC {
+		size += 2
+	}
+	if h.Extension != nil {
+		size += h.Extension.size()
+	}
+
+	return size + h.Stuffing
+}
+
+// Encode writes PES header into b.
+// Returns number of bytes written.
+func (h *PESHeader) Encode(b []byte) (int, error) {
+	size := h.Size()
+	if len(b) < size || (size-PesHeaderSize-PesOptionalHeaderSize) > 0xFF {
+		return 0, ErrPesHeader
+	}
+
+	if h.HasDTS && !h.HasPTS {
+		return 0, ErrPesHeader
+	}
+
+	p := PES(b[:size])
+	p.SetPrefix()
+	p.SetStreamID(h.StreamID)
+	p.SetLength(h.Length)
+
+	if !p.IsES() {
+		return size, nil
+	}
+
+	p[6] = 0x80
+	p.SetScramblingControl(h.ScramblingControl)
+	p.SetPriority(h.Priority)
+	p.SetDataAlignment(h.DataAlignment)
+	p.SetCopyright(h.Copyright)
+	p.SetOriginal(h.Original)
+
+	p[7] = 0x00
+	p[8] = byte(size - PesHeaderSize - PesOptionalHeaderSize)
+
+	skip := PesHeaderSize + PesOptionalHeaderSize
+
+	if h.HasPTS {
+		if h.HasDTS {
+			p[7] |= pesFlagPTS | pesFlagDTS
+			setTimestamp(p[skip:], 0x30, h.PTS)
+			setTimestamp(p[skip+5:], 0x10, h.DTS)
+			skip += 10
+		} else {
+			p[7] |= pesFlagPTS
+			setTimestamp(p[skip:], 0x20, h.PTS)
+			skip += 5
+		}
+	}
+
+	if h.HasESCR {
+		p[7] |= pesFlagESCR
+		setESCR(p[skip:], h.ESCR)
+		skip += 6
+	}
+
+	if h.HasESRate {
+		p[7] |= pesFlagESRate
+		setESRate(p[skip:], h.ESRate)
+		skip += 3
+	}
+
+	if h.HasTrickMode {
+		p[7] |= pesFlagTrickMode
+		p[skip] = h.TrickMode
+		skip += 1
+	}
+
+	if h.HasAdditionalCopyInfo {
+		p[7] |= pesFlagAdditionalCopy
+		p[skip] = 0x80 | (h.AdditionalCopyInfo & 0x7F)
+		skip += 1
+	}
+
+	if h.HasPreviousCRC {
+		p[7] |= pesFlagCRC
+		p[skip] = byte(h.PreviousCRC >> 8)
+		p[skip+1] = byte(h.PreviousCRC)
+		skip += 2
+	}
+
+	if h.Extension != nil {
+		p[7] |= pesFlagExtension
+		if err := h.Extension.encode(p[skip:]); err != nil {
+			return 0, err
+		}
+		skip += h.Extension.size()
+	}
+
+	for ; skip < size; skip++ {
+		p[skip] = 0xFF
+	}
+
+	return size, nil
+}
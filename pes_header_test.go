@@ -0,0 +1,203 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPES_Payload(t *testing.T) {
+	assert := assert.New(t)
+
+	pes := PES{
+		0x00, 0x00, 0x01, 0xC0, 0x00, 0x0A, 0x84, 0x80,
+		0x05, 0x21, 0x00, 0x01, 0x00, 0x01, 0xAA, 0xBB,
+	}
+
+	assert.Equal(10, pes.Length())
+	assert.Equal(5, pes.HeaderLength())
+	assert.True(pes.IsDataAligned())
+	assert.False(pes.HasCopyright())
+	assert.Equal([]byte{0xAA, 0xBB}, pes.Payload())
+
+	// header length out of range
+	pes[8] = 0x20
+	assert.Nil(pes.Payload())
+
+	// PTS out of buffer
+	assert.Equal(NonTimestamp, pes[:12].PTS())
+}
+
+func TestPESHeader_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	pes := PES{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x99, 0xFF,
+		0x20,
+		// PTS, DTS
+		0x31, 0x00, 0x0D, 0xDF, 0x89,
+		0x11, 0x00, 0x0D, 0xC8, 0x13,
+		// ESCR: base 1, ext 2
+		0xC4, 0x00, 0x04, 0x00, 0x0C, 0x05,
+		// ES_rate: 1000
+		0x80, 0x07, 0xD1,
+		// trick mode: slow motion
+		0x25,
+		// additional copy info
+		0xAA,
+		// previous CRC
+		0x12, 0x34,
+		// extension: sequence counter, P-STD buffer, extension 2
+		0x3F,
+		0x85, 0xC3,
+		0x60, 0x10,
+		0x81, 0x71,
+		// stuffing
+		0xFF, 0xFF,
+	}
+
+	var h PESHeader
+	if !assert.NoError(h.Decode(pes)) {
+		return
+	}
+
+	assert.Equal(uint8(0xE0), h.StreamID)
+	assert.Equal(0, h.Length)
+	assert.Equal(ScramblingControl(1), h.ScramblingControl)
+	assert.True(h.Priority)
+	assert.False(h.DataAlignment)
+	assert.False(h.Copyright)
+	assert.True(h.Original)
+	assert.Equal(Timestamp(225220), h.PTS)
+	assert.Equal(Timestamp(222217), h.DTS)
+	assert.True(h.HasESCR)
+	assert.Equal(PCR(302), h.ESCR)
+	assert.Equal(uint32(1000), h.ESRate)
+	assert.Equal(uint8(TrickModeSlowMotion), h.TrickModeControl())
+	assert.Equal(uint8(0x2A), h.AdditionalCopyInfo)
+	assert.Equal(uint16(0x1234), h.PreviousCRC)
+	assert.Equal(2, h.Stuffing)
+
+	if assert.NotNil(h.Extension) {
+		e := h.Extension
+		assert.Nil(e.PrivateData)
+		assert.Nil(e.PackHeader)
+		assert.True(e.HasSequenceCounter)
+		assert.Equal(uint8(5), e.SequenceCounter)
+		assert.True(e.MPEG1)
+		assert.Equal(uint8(3), e.OriginalStuffLength)
+		assert.True(e.HasPSTDBuffer)
+		assert.Equal(uint8(1), e.PSTDBufferScale)
+		assert.Equal(uint16(16), e.PSTDBufferSize)
+
+		id, ok := e.StreamIDExtension()
+		assert.True(ok)
+		assert.Equal(uint8(0x71), id)
+	}
+
+	// view accessors
+	assert.Equal(PCR(302), pes.ESCR())
+	assert.Equal(uint32(1000), pes.ESRate())
+	assert.Equal(uint8(0x25), pes.TrickMode())
+	assert.Equal(uint8(0x2A), pes.AdditionalCopyInfo())
+	assert.Equal(uint16(0x1234), pes.PreviousCRC())
+
+	ext, err := pes.Extension()
+	assert.NoError(err)
+	assert.Equal(h.Extension, ext)
+
+	// encode back
+	b := make([]byte, h.Size())
+	n, err := h.Encode(b)
+	assert.NoError(err)
+	assert.Equal(len(pes), n)
+	assert.Equal([]byte(pes), b)
+}
+
+func TestPESHeader_DecodeError(t *testing.T) {
+	assert := assert.New(t)
+
+	var h PESHeader
+
+	// header data length out of buffer
+	pes := PES{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0x80,
+		0x05, 0x21, 0x00, 0x01,
+	}
+	assert.ErrorIs(h.Decode(pes), ErrPesHeader)
+
+	// fields out of header data length
+	pes = PES{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0xC0,
+		0x05, 0x31, 0x00, 0x01, 0x00, 0x01,
+	}
+	assert.ErrorIs(h.Decode(pes), ErrPesHeader)
+
+	// DTS without PTS
+	pes = PES{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0x40,
+		0x05, 0x11, 0x00, 0x01, 0x00, 0x01,
+	}
+	assert.ErrorIs(h.Decode(pes), ErrPesHeader)
+
+	// extension out of range
+	pes = PES{
+		0x00, 0x00, 0x01, 0xE0, 0x00, 0x00, 0x80, 0x01,
+		0x02, 0x80, 0x00,
+	}
+	assert.ErrorIs(h.Decode(pes), ErrPesHeader)
+	assert.Nil(h.Extension)
+}
+
+func TestPESHeader_Encode(t *testing.T) {
+	assert := assert.New(t)
+
+	private := make([]byte, 16)
+	for i := range private {
+		private[i] = byte(i)
+	}
+
+	h := PESHeader{
+		StreamID:      0xBD,
+		Length:        100,
+		DataAlignment: true,
+		HasPTS:        true,
+		PTS:           90000,
+		HasESCR:       true,
+		ESCR:          27000000*3 + 17,
+		Extension: &PESExtension{
+			PrivateData: private,
+			PackHeader:  []byte{0x00, 0x00, 0x01, 0xBA},
+		},
+	}
+
+	b := make([]byte, h.Size())
+	n, err := h.Encode(b)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(len(b), n)
+
+	pes := PES(b)
+	assert.Equal(90000, int(pes.PTS()))
+	assert.Equal(h.ESCR, pes.ESCR())
+	assert.Equal(100, pes.Length())
+
+	var d PESHeader
+	if assert.NoError(d.Decode(pes)) {
+		assert.Equal(h, d)
+	}
+
+	// stream without optional header
+	h = PESHeader{StreamID: 0xBE, Length: 10}
+	b = make([]byte, h.Size())
+	n, err = h.Encode(b)
+	assert.NoError(err)
+	assert.Equal(PesHeaderSize, n)
+	assert.Equal([]byte{0x00, 0x00, 0x01, 0xBE, 0x00, 0x0A}, b)
+
+	// short buffer
+	h = PESHeader{StreamID: 0xE0, HasPTS: true}
+	_, err = h.Encode(make([]byte, 10))
+	assert.ErrorIs(err, ErrPesHeader)
+}
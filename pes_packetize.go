@@ -0,0 +1,164 @@
+package mpegts
+
+import (
+	"errors"
+)
+
+const (
+	// PES header with PTS and DTS
+	pesMaximumHeaderSize = PesHeaderSize + PesOptionalHeaderSize + 10
+
+	// Maximum value of the PES_packet_length
+	pesMaximumLength = 0xFFFF
+)
+
+var (
+	ErrPesSize = errors.New("pes: payload too large")
+)
+
+// PESPacketizer is a helper to split elementary stream access unit
+// into TS packets. Packetizer makes PES header, sets PUSI and
+// continuity counter, and puts stuffing into the last packet.
+type PESPacketizer struct {
+	pid      PID
+	streamID uint8
+	cc       uint8
+
+	header [pesMaximumHeaderSize]byte
+	size   int // header size
+
+	payload []byte
+	skip    int // bytes of header and payload in packets
+
+	af AdaptationField // adaptation field for the first packet
+}
+
+// NewPesPacketizer returns a new PESPacketizer for the elementary stream
+func NewPesPacketizer(pid PID, streamID uint8) *PESPacketizer {
+	return &PESPacketizer{
+		pid:      pid,
+		streamID: streamID,
+		cc:       0x0F,
+	}
+}
+
+// SetCC sets continuity counter of the last sent packet.
+// Next packet will have cc + 1.
+func (p *PESPacketizer) SetCC(cc uint8) {
+	p.cc = cc & 0x0F
+}
+
+// Begin prepares PES with access unit payload.
+// pts and dts could be NonTimestamp if not defined.
+// DTS is not included if it is equal to PTS.
+// payload should not be modified till the last packet.
+// PES_packet_length is 0 if payload is larger than 65535 bytes,
+// it is allowed only for video streams (stream_id 0xE0-0xEF).
+// Returns ErrPesSize for other streams, Next returns false in this case.
+func (p *PESPacketizer) Begin(payload []byte, pts, dts Timestamp) error {
+	p.payload = nil
+	p.size = 0
+	p.skip = 0
+	p.af = AdaptationField{}
+
+	pes := PES(p.header[:])
+	pes.SetPrefix()
+	pes.SetStreamID(p.streamID)
+	pes[6] = 0x80 // marker bits
+	pes[7] = 0x00 // flags
+	pes[8] = 0x00 // PES_header_data_length
+
+	size := PesHeaderSize + PesOptionalHeaderSize
+
+	if pts != NonTimestamp {
+		pes[8] = 5
+		pes.SetPTS(pts)
+		size += 5
+
+		if dts != NonTimestamp && dts != pts {
+			pes[8] = 10
+			pes.SetDTS(dts)
+			size += 5
+		}
+	}
+
+	length := size - PesHeaderSize + len(payload)
+	if length > pesMaximumLength {
+		// unbounded PES allowed only for video elementary streams
+		if !isVideoStream(p.streamID) {
+			return ErrPesSize
+		}
+		length = 0
+	}
+	pes.SetLength(length)
+
+	p.payload = payload
+	p.size = size
+
+	return nil
+}
+
+// isVideoStream checks stream_id of the video elementary stream
+func isVideoStream(streamID uint8) bool {
+	return streamID >= 0xE0 && streamID <= 0xEF
+}
+
+// SetPCR inserts PCR into the adaptation field of the first packet.
+// Should be called after Begin.
+func (p *PESPacketizer) SetPCR(pcr PCR) {
+	p.af.HasPCR = true
+	p.af.PCR = pcr
+}
+
+// SetRandomAccess sets random_access_indicator in the first packet.
+// Should be called after Begin.
+func (p *PESPacketizer) SetRandomAccess() {
+	p.af.RandomAccess = true
+}
+
+// Next puts next TS packet into ts.
+// Returns false if no more data to packetize.
+func (p *PESPacketizer) Next(ts TS) bool {
+	total := p.size + len(p.payload)
+	if p.skip >= total {
+		return false
+	}
+
+	p.cc = (p.cc + 1) & 0x0F
+
+	ts[0] = SyncByte
+	ts[1] = 0x00
+	ts[2] = 0x00
+	ts[3] = 0x10 | p.cc // payload without adaptation field
+	ts.SetPID(p.pid)
+
+	fill := 4
+
+	if p.skip == 0 {
+		ts.SetPUSI()
+
+		if p.af.HasPCR || p.af.RandomAccess {
+			fill, _ = p.af.Encode(ts)
+		}
+	}
+
+	// PES header
+	if p.skip < p.size {
+		n := copy(ts[fill:], p.header[p.skip:p.size])
+		fill += n
+		p.skip += n
+	}
+
+	// PES payload
+	if fill < PacketSize {
+		n := copy(ts[fill:], p.payload[p.skip-p.size:])
+		fill += n
+		p.skip += n
+	}
+
+	if fill < PacketSize {
+		ts.Fill(fill)
+	}
+
+	return true
+}
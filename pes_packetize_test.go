@@ -0,0 +1,126 @@
+package mpegts
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExamplePESPacketizer() {
+	packetizer := NewPesPacketizer(256, 0xC0)
+	if err := packetizer.Begin(make([]byte, 300), 90000, NonTimestamp); err != nil {
+		panic(err)
+	}
+	packetizer.SetPCR(27000000)
+
+	ts := NewTS(256)
+	for packetizer.Next(ts) {
+		fmt.Printf("%X...\n", ts[:20])
+	}
+
+	// Output:
+	// 4741003007100000AFC87E00000001C001348080...
+	// 470100312D00FFFFFFFFFFFFFFFFFFFFFFFFFFFF...
+}
+
+func TestPESPacketizer(t *testing.T) {
+	for _, size := range []int{0, 100, 170, 171, 172, 500, 70000} {
+		t.Run(fmt.Sprintf("size %d", size), func(t *testing.T) {
+			assert := assert.New(t)
+
+			payload := make([]byte, size)
+			for i := range payload {
+				payload[i] = byte(i)
+			}
+
+			packetizer := NewPesPacketizer(256, 0xE0)
+			packetizer.SetCC(5)
+			assert.NoError(packetizer.Begin(payload, 1000, 900))
+			packetizer.SetPCR(900 * 300)
+			packetizer.SetRandomAccess()
+
+			assembler := PESAssembler{}
+			count := 0
+			cc := uint8(5)
+
+			onPes := func(err error) {
+				if !assert.NoError(err) {
+					return
+				}
+
+				pes := assembler.PES()
+				assert.Equal(uint8(0xE0), pes.StreamID())
+				assert.Equal(Timestamp(1000), pes.PTS())
+				assert.Equal(Timestamp(900), pes.DTS())
+				assert.Equal(payload, []byte(pes[19:]))
+				count += 1
+			}
+
+			ts := NewTS(256)
+			first := true
+			for packetizer.Next(ts) {
+				assert.Equal(PID(256), ts.PID())
+				_, ok := ts.CheckCC(cc)
+				assert.True(ok)
+				cc = ts.CC()
+
+				if first {
+					assert.True(ts.HasPUSI())
+					assert.True(ts.HasRandomAccess())
+					assert.Equal(PCR(900*300), ts.PCR())
+					first = false
+				} else {
+					assert.False(ts.HasPUSI())
+				}
+
+				assembler.Assemble(ts, onPes)
+			}
+			assembler.Flush(onPes)
+
+			assert.Equal(1, count)
+		})
+	}
+}
+
+func TestPESPacketizer_WithoutTimestamp(t *testing.T) {
+	assert := assert.New(t)
+
+	packetizer := NewPesPacketizer(256, 0xBD)
+	assert.NoError(packetizer.Begin([]byte{1, 2, 3}, NonTimestamp, NonTimestamp))
+
+	ts := NewTS(256)
+	assert.True(packetizer.Next(ts))
+
+	expected := []byte{
+		0x47, 0x41, 0x00, 0x30, 0xAB, 0x00,
+	}
+	assert.Equal(expected, []byte(ts[:6]))
+	assert.Equal(
+		[]byte{0x00, 0x00, 0x01, 0xBD, 0x00, 0x06, 0x80, 0x00, 0x00, 1, 2, 3},
+		[]byte(ts[PacketSize-12:]),
+	)
+
+	assert.False(packetizer.Next(ts))
+}
+
+func TestPESPacketizer_Size(t *testing.T) {
+	assert := assert.New(t)
+
+	packetizer := NewPesPacketizer(256, 0xC0)
+	ts := NewTS(256)
+
+	// PES_packet_length includes 3 bytes of the optional header and 5 bytes of PTS
+	assert.NoError(packetizer.Begin(make([]byte, pesMaximumLength-8), 1000, NonTimestamp))
+	assert.True(packetizer.Next(ts))
+	assert.Equal(pesMaximumLength, PES(ts[4:]).Length())
+
+	assert.ErrorIs(packetizer.Begin(make([]byte, pesMaximumLength-7), 1000, NonTimestamp), ErrPesSize)
+	assert.False(packetizer.Next(ts))
+
+	// unbounded PES for video stream
+	packetizer = NewPesPacketizer(256, 0xE0)
+	assert.NoError(packetizer.Begin(make([]byte, pesMaximumLength), 1000, NonTimestamp))
+	assert.True(packetizer.Next(ts))
+	assert.Equal(0, PES(ts[4:]).Length())
+}
@@ -33,3 +33,27 @@ func (t Timestamp) Delta(previous Timestamp) Timestamp {
 func (t Timestamp) Add(u Timestamp) Timestamp {
 	return (t + u) & MaxTimestamp
 }
+
+// getTimestamp returns 33-bit timestamp from 5 bytes with marker bits
+// as used in PTS, DTS and DTS_next_AU fields
+func getTimestamp(b []byte) Timestamp {
+	_ = b[4]
+	return (Timestamp(b[0]&0x0E) << 29) |
+		(Timestamp(b[1]) << 22) |
+		(Timestamp(b[2]&0xFE) << 14) |
+		(Timestamp(b[3]) << 7) |
+		(Timestamp(b[4]) >> 1)
+}
+
+// setTimestamp puts 33-bit timestamp into 5 bytes with marker bits.
+// prefix is a 4-bit value in the high bits of the first byte.
+func setTimestamp(b []byte, prefix byte, value Timestamp) {
+	_ = b[4]
+	value &= MaxTimestamp
+
+	b[0] = (prefix & 0xF0) | byte(value>>29) | 0x01
+	b[1] = byte(value >> 22)
+	b[2] = byte(value>>14) | 0x01
+	b[3] = byte(value >> 7)
+	b[4] = byte(value<<1) | 0x01
+}
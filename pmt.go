@@ -158,18 +158,41 @@ func (p *PMT) Descriptors() Descriptors {
 	return Descriptors(p.header[PmtHeaderSize:])
 }
 
-func (p *PMT) AppendDescriptors(desc Descriptors) {
+// AppendDescriptors appends descriptors to the program descriptors.
+// Returns ErrDescriptorSize if program_info_length exceeds the limit.
+func (p *PMT) AppendDescriptors(desc Descriptors) error {
 	if len(desc) == 0 {
-		return
+		return nil
 	}
 
 	ds := len(p.header) - PmtHeaderSize + len(desc)
 	if ds > 0x03FF {
-		panic("failed to append pmt descriptors: size limit")
+		return ErrDescriptorSize
 	}
 	p.header = append(p.header, desc...)
 
 	binary.BigEndian.PutUint16(p.header[10:], 0xF000|uint16(ds))
+	return nil
+}
+
+// SetDescriptors replaces program descriptors.
+// Use Descriptors methods to edit the descriptors loop:
+//
+//	pmt.SetDescriptors(pmt.Descriptors().Remove(0x09))
+func (p *PMT) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > 0x03FF {
+		return ErrDescriptorSize
+	}
+
+	// new buffer for header: desc could be a part of the current header
+	p.header = append(p.header[:PmtHeaderSize:PmtHeaderSize], desc...)
+
+	binary.BigEndian.PutUint16(p.header[10:], 0xF000|uint16(len(desc)))
+	return nil
 }
 
 // Calculates LastSectionNumber
@@ -277,18 +300,37 @@ func (p *PmtItem) Descriptors() Descriptors {
 	return Descriptors(p.header[PmtItemSize:])
 }
 
-func (p *PmtItem) AppendDescriptors(desc Descriptors) {
+// AppendDescriptors appends descriptors to the elementary stream descriptors.
+// Returns ErrDescriptorSize if ES_info_length exceeds the limit.
+func (p *PmtItem) AppendDescriptors(desc Descriptors) error {
 	if len(desc) == 0 {
-		return
+		return nil
 	}
 
 	ds := len(p.header) - PmtItemSize + len(desc)
 	if ds > 0x03FF {
-		panic("failed to append pmt item descriptors: size limit")
+		return ErrDescriptorSize
 	}
 	p.header = append(p.header, desc...)
 
 	binary.BigEndian.PutUint16(p.header[3:], 0xF000|uint16(ds))
+	return nil
+}
+
+// SetDescriptors replaces elementary stream descriptors
+func (p *PmtItem) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > 0x03FF {
+		return ErrDescriptorSize
+	}
+
+	p.header = append(p.header[:PmtItemSize:PmtItemSize], desc...)
+
+	binary.BigEndian.PutUint16(p.header[3:], 0xF000|uint16(len(desc)))
+	return nil
 }
 
 func (p *PmtItem) checkData05() StreamType {
@@ -137,3 +137,58 @@ func TestPMT_Packetize(t *testing.T) {
 		assert.Equal(1, counter)
 	})
 }
+
+func TestPMT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	pmt := NewPmt()
+	assert.NoError(pmt.AppendDescriptors(Descriptors{0x09, 0x04, 0x01, 0x00, 0xE1, 0x00}))
+
+	item := NewPmtItem()
+	item.SetType(0x1B)
+	item.SetPID(256)
+	assert.NoError(item.AppendDescriptors(Descriptors{
+		0x09, 0x04, 0x01, 0x00, 0xE1, 0x01,
+		0x52, 0x01, 0x01,
+	}))
+	pmt.Items = append(pmt.Items, item)
+
+	// strip CA descriptors
+	assert.NoError(pmt.SetDescriptors(pmt.Descriptors().Remove(0x09)))
+	assert.Equal(0, len(pmt.Descriptors()))
+	assert.NoError(item.SetDescriptors(item.Descriptors().Remove(0x09)))
+	assert.Equal(Descriptors{0x52, 0x01, 0x01}, item.Descriptors())
+
+	pmt.Finalize()
+
+	psi := new(PSI)
+	ts := NewTS(100)
+	for p := pmt.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(err) {
+				return
+			}
+
+			decoded := NewPmt()
+			if !assert.NoError(decoded.ParsePmtSection(psi.Payload())) {
+				return
+			}
+
+			assert.Equal(0, len(decoded.Descriptors()))
+			if assert.Equal(1, len(decoded.Items)) {
+				assert.Equal(Descriptors{0x52, 0x01, 0x01}, decoded.Items[0].Descriptors())
+			}
+		})
+	}
+
+	// size limit
+	large := make(Descriptors, 0, 0x0500)
+	for len(large) <= 0x03FF {
+		large = append(large, 0x80, 0xFE)
+		large = append(large, make([]byte, 0xFE)...)
+	}
+	assert.ErrorIs(pmt.AppendDescriptors(large), ErrDescriptorSize)
+	assert.ErrorIs(item.AppendDescriptors(large), ErrDescriptorSize)
+	assert.ErrorIs(item.SetDescriptors(Descriptors{0x52, 0x05}), ErrDescriptorFormat)
+	assert.Equal(Descriptors{0x52, 0x01, 0x01}, item.Descriptors())
+}
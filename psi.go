@@ -26,6 +26,11 @@ type PSI struct {
 	LastSectionNumber uint8
 	CRC               uint32
 
+	// table_id_extension: transport_stream_id for PAT and SDT,
+	// program_number for PMT, network_id for NIT, bouquet_id for BAT,
+	// service_id for EIT
+	TableIDExtension uint16
+
 	cc byte
 
 	buffer [PsiMaximumSize]byte // PSI buffer
@@ -86,6 +91,7 @@ func (p *PSI) commonCheck(minSize, maxSize int, crc bool) error {
 	}
 
 	p.TableID = p.buffer[0]
+	p.TableIDExtension = binary.BigEndian.Uint16(p.buffer[3:])
 	p.Version = (p.buffer[5] >> 1) & 0x1F
 	p.SectionNumber = p.buffer[6]
 	p.LastSectionNumber = p.buffer[7]
@@ -97,21 +103,89 @@ func (p *PSI) commonCheck(minSize, maxSize int, crc bool) error {
 	return nil
 }
 
+// Checks of short PSI section without section_syntax_indicator.
+// Version and section numbers are not defined for short sections.
+func (p *PSI) shortCheck(minSize, maxSize int, crc bool) error {
+	if crc {
+		minSize += crc32.Size
+	}
+
+	if p.size < minSize || p.size > maxSize {
+		return ErrPsiFormat
+	}
+
+	if crc {
+		skip := p.size - crc32.Size
+		crcActual := crc32.Checksum(0xFFFFFFFF, p.buffer[:skip])
+		crcExpected := binary.BigEndian.Uint32(p.buffer[skip:])
+
+		if crcActual != crcExpected {
+			return ErrCRC
+		}
+
+		p.CRC = crcActual
+	}
+
+	p.TableID = p.buffer[0]
+	p.TableIDExtension = 0
+	p.Version = 0
+	p.SectionNumber = 0
+	p.LastSectionNumber = 0
+
+	return nil
+}
+
 func (p *PSI) assembleCheck() error {
 	switch p.buffer[0] {
 	case 0x00: // PAT
 		return p.commonCheck(PatHeaderSize, PatMaximumSize, true)
 	case 0x01: // CAT
-		return p.commonCheck(12, 1024, true)
+		return p.commonCheck(CatHeaderSize, CatMaximumSize, true)
 	case 0x02: // PMT
 		return p.commonCheck(PmtHeaderSize, PmtMaximumSize, true)
+	case 0x40: // NIT Actual
+		return p.commonCheck(NitHeaderSize+nitLoopSize, NitMaximumSize, true)
+	case 0x41: // NIT Other
+		return p.commonCheck(NitHeaderSize+nitLoopSize, NitMaximumSize, true)
 	case 0x42: // SDT Actual
 		return p.commonCheck(SdtHeaderSize, SdtMaximumSize, true)
 	case 0x46: // SDT Other
 		return p.commonCheck(SdtHeaderSize, SdtMaximumSize, true)
+	case 0x4A: // BAT
+		return p.commonCheck(BatHeaderSize+batLoopSize, BatMaximumSize, true)
+	case 0x70: // TDT
+		return p.shortCheck(TdtSize, TdtSize, false)
+	case 0x73: // TOT
+		return p.shortCheck(TotHeaderSize, TotMaximumSize, true)
+	case 0xFC: // SCTE-35 splice_info_section
+		return p.shortCheck(SpliceInfoHeaderSize+2, SpliceInfoMaximumSize, true)
 	}
 
-	return nil
+	if p.buffer[0] >= 0x4E && p.buffer[0] <= 0x6F { // EIT
+		return p.commonCheck(EitHeaderSize, EitMaximumSize, true)
+	}
+
+	return p.sectionCheck()
+}
+
+// Checks of generic section for tables without own format.
+// Long sections have version, section numbers, and checksum.
+func (p *PSI) sectionCheck() error {
+	s := Section(p.Payload())
+	if err := s.Check(); err != nil {
+		if err == ErrCRC {
+			return err
+		}
+		return ErrPsiFormat
+	}
+
+	if !s.IsLong() {
+		return p.shortCheck(PsiHeaderSize, PsiMaximumSize, false)
+	}
+
+	p.CRC = s.CRC()
+
+	return p.commonCheck(SectionLongHeaderSize, PsiMaximumSize, false)
 }
 
 func (p *PSI) callAssembleFn(fn AssembleFn, err error) {
@@ -124,29 +198,36 @@ func (p *PSI) callAssembleFn(fn AssembleFn, err error) {
 	p.Clear()
 }
 
-func (p *PSI) assembleStep(payload []byte) error {
+// assembleStep appends payload to the buffer.
+// Returns number of bytes consumed from payload.
+func (p *PSI) assembleStep(payload []byte) (int, error) {
+	consumed := 0
+
 	if p.size == 0 {
 		// p.skip less than PsiHeaderSize if p.size == 0
 		skip := copy(p.buffer[p.skip:PsiHeaderSize], payload)
 		p.skip += skip
+		consumed = skip
 
 		if p.skip == PsiHeaderSize {
 			p.size = p.getSectionLength()
 			if p.size > PsiMaximumSize {
-				return ErrAssemblePSI
+				return consumed, ErrAssemblePSI
 			}
 			payload = payload[skip:]
 		} else if skip == p.skip {
 			// after pointer field less than 3 bytes
-			return nil
+			return consumed, nil
 		} else {
 			// 1 byte in buffer and 1 byte in payload
-			return ErrAssemblePSI
+			return consumed, ErrAssemblePSI
 		}
 	}
 
-	p.skip += copy(p.buffer[p.skip:p.size], payload)
-	return nil
+	n := copy(p.buffer[p.skip:p.size], payload)
+	p.skip += n
+
+	return consumed + n, nil
 }
 
 func (p *PSI) getSectionLength() int {
@@ -154,15 +235,19 @@ func (p *PSI) getSectionLength() int {
 	return PsiHeaderSize + int(binary.BigEndian.Uint16(p.buffer[1:])&0x0FFF)
 }
 
-// Assembles TS packets into single PSI.
-// Calls fn when PSI is ready or error occurs
+// Assembles TS packets into PSI sections.
+// Calls fn when PSI is ready or error occurs.
+// Packet with payload start may contain tail of the previous section
+// and several sections one after another till the stuffing bytes.
 func (p *PSI) Assemble(packet TS, fn AssembleFn) {
 	payload := packet.Payload()
 	if payload == nil {
 		return
 	}
 
-	if packet.HasPUSI() {
+	pusi := packet.HasPUSI()
+
+	if pusi {
 		remain := int(payload[0])
 		payload = payload[1:]
 
@@ -174,7 +259,7 @@ func (p *PSI) Assemble(packet TS, fn AssembleFn) {
 		if p.skip != 0 {
 			if packet.CC() != (p.cc+1)&0x0F {
 				p.callAssembleFn(fn, ErrCC)
-			} else if err := p.assembleStep(payload[:remain]); err != nil {
+			} else if _, err := p.assembleStep(payload[:remain]); err != nil {
 				p.callAssembleFn(fn, err)
 			} else if p.skip == p.size {
 				p.callAssembleFn(fn, nil)
@@ -195,13 +280,31 @@ func (p *PSI) Assemble(packet TS, fn AssembleFn) {
 		}
 	}
 
-	if err := p.assembleStep(payload); err != nil {
-		p.callAssembleFn(fn, err)
-		return
-	}
+	for len(payload) != 0 {
+		// stuffing after the last section
+		if p.skip == 0 && payload[0] == 0xFF {
+			break
+		}
+
+		n, err := p.assembleStep(payload)
+		if err != nil {
+			p.callAssembleFn(fn, err)
+			return
+		}
+
+		if p.skip != p.size {
+			// section continues in the next packet
+			break
+		}
 
-	if p.skip == p.size {
 		p.callAssembleFn(fn, nil)
+
+		// new section could be started only in the packet with payload start
+		if !pusi {
+			break
+		}
+
+		payload = payload[n:]
 	}
 
 	p.cc = packet.CC()
@@ -0,0 +1,127 @@
+package mpegts
+
+import (
+	"sort"
+)
+
+// TableFn is a TableCollector callback with complete table.
+// Sections are ordered by section_number.
+// Sections are valid till TableFn returns.
+type TableFn func(psi *PSI, sections [][]byte)
+
+// TableCollector collects PSI sections into complete tables.
+// Tables are identified by table_id and table_id_extension.
+// transport_stream_id and original_network_id are not part of the key:
+// EIT other and SDT other sub-tables of different transport streams
+// with the same service_id or transport_stream_id collide,
+// use separate TableCollector for each transport stream.
+// Sections with current_next_indicator set to 0 are ignored.
+// Repeats of the complete table with the same version are ignored.
+// Table is collected again on version change.
+type TableCollector struct {
+	tables map[uint32]*collectorTable
+}
+
+type collectorTable struct {
+	version  uint8
+	last     uint8 // last_section_number
+	complete bool
+
+	received [256]bool
+	remain   int // number of sections to receive
+	sections [][]byte
+}
+
+func NewTableCollector() *TableCollector {
+	return &TableCollector{
+		tables: make(map[uint32]*collectorTable),
+	}
+}
+
+func collectorKey(tableID uint8, tableIDExtension uint16) uint32 {
+	return uint32(tableID)<<16 | uint32(tableIDExtension)
+}
+
+// Reset removes all tables. Next sections will be collected from scratch.
+func (c *TableCollector) Reset() {
+	c.tables = make(map[uint32]*collectorTable)
+}
+
+// Remove removes table. Next sections of the table will be collected from scratch.
+func (c *TableCollector) Remove(tableID uint8, tableIDExtension uint16) {
+	delete(c.tables, collectorKey(tableID, tableIDExtension))
+}
+
+func (t *collectorTable) init(psi *PSI) {
+	t.version = psi.Version
+	t.last = psi.LastSectionNumber
+	t.complete = false
+	t.received = [256]bool{}
+	t.remain = int(psi.LastSectionNumber) + 1
+	t.sections = t.sections[:0]
+}
+
+func (t *collectorTable) mark(number uint8) {
+	if !t.received[number] {
+		t.received[number] = true
+		t.remain -= 1
+	}
+}
+
+// Collect appends assembled section to the table.
+// Should be called in AssembleFn if PSI assembled without error.
+// Calls fn when all sections of the table are received.
+// Sections without section_syntax_indicator are ignored.
+func (c *TableCollector) Collect(psi *PSI, fn TableFn) {
+	b := psi.Payload()
+	if len(b) < 8 || (b[1]&0x80) == 0 || !isCurrentSection(b) {
+		return
+	}
+
+	key := collectorKey(psi.TableID, psi.TableIDExtension)
+	t, ok := c.tables[key]
+	if !ok {
+		t = new(collectorTable)
+		t.init(psi)
+		c.tables[key] = t
+	} else if t.version != psi.Version || t.last != psi.LastSectionNumber {
+		t.init(psi)
+	} else if t.complete || t.received[psi.SectionNumber] {
+		return
+	}
+
+	section := make([]byte, len(b))
+	copy(section, b)
+	t.sections = append(t.sections, section)
+	t.mark(psi.SectionNumber)
+
+	// EIT schedule contains segments of 8 sections.
+	// Sections after segment_last_section_number are not transmitted.
+	if psi.TableID >= 0x50 && psi.TableID <= 0x6F && len(b) >= EitHeaderSize {
+		segmentLast := b[12]
+		segmentEnd := psi.SectionNumber | (eitSegmentSections - 1)
+		if segmentEnd > t.last {
+			segmentEnd = t.last
+		}
+
+		for n := int(segmentLast) + 1; n <= int(segmentEnd); n++ {
+			t.mark(uint8(n))
+		}
+	}
+
+	if t.remain != 0 {
+		return
+	}
+
+	t.complete = true
+
+	sortSections(t.sections)
+	fn(psi, t.sections)
+}
+
+// sortSections sorts sections by section_number
+func sortSections(sections [][]byte) {
+	sort.Slice(sections, func(i, j int) bool {
+		return sections[i][6] < sections[j][6]
+	})
+}
@@ -0,0 +1,212 @@
+package mpegts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeCollectorPat(version uint8, count int) *PAT {
+	pat := NewPat()
+	pat.SetVersion(version)
+	pat.SetTSID(1)
+
+	for i := 0; i < count; i++ {
+		item := NewPatItem()
+		item.SetPNR(uint16(i + 1))
+		item.SetPID(PID(i + 100))
+		pat.Items = append(pat.Items, item)
+	}
+
+	pat.Finalize()
+
+	return pat
+}
+
+func collectTable(c *TableCollector, p *PsiPacketizer, pid PID, fn TableFn) {
+	psi := new(PSI)
+	ts := NewTS(pid)
+	for ; p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if err == nil {
+				c.Collect(psi, fn)
+			}
+		})
+	}
+}
+
+func TestTableCollector_PAT(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewTableCollector()
+
+	var result *PAT
+	calls := 0
+	onTable := func(psi *PSI, sections [][]byte) {
+		calls += 1
+		assert.Equal(uint8(0x00), psi.TableID)
+
+		result = new(PAT)
+		for _, b := range sections {
+			assert.NoError(result.ParsePatSection(b))
+		}
+	}
+
+	// 300 programs in 2 sections
+	pat := makeCollectorPat(1, 300)
+	assert.Equal(uint8(1), pat.header[7])
+
+	collectTable(c, pat.Packetizer(), 0, onTable)
+	assert.Equal(1, calls)
+	if assert.NotNil(result) {
+		assert.Equal(uint8(1), result.Version())
+		assert.Equal(300, len(result.Items))
+	}
+
+	// repeat of the same version is ignored
+	collectTable(c, pat.Packetizer(), 0, onTable)
+	collectTable(c, pat.Packetizer(), 0, onTable)
+	assert.Equal(1, calls)
+
+	// next version
+	pat = makeCollectorPat(2, 10)
+	collectTable(c, pat.Packetizer(), 0, onTable)
+	assert.Equal(2, calls)
+	if assert.NotNil(result) {
+		assert.Equal(uint8(2), result.Version())
+		assert.Equal(10, len(result.Items))
+	}
+
+	// next table with current_next_indicator is 0
+	pat = makeCollectorPat(3, 10)
+	pat.header[5] &^= 0x01
+	collectTable(c, pat.Packetizer(), 0, onTable)
+	assert.Equal(2, calls)
+
+	// table collected again after reset
+	c.Reset()
+	collectTable(c, makeCollectorPat(2, 10).Packetizer(), 0, onTable)
+	assert.Equal(3, calls)
+}
+
+func TestTableCollector_Incomplete(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewTableCollector()
+
+	calls := 0
+	onTable := func(psi *PSI, sections [][]byte) {
+		calls += 1
+		assert.Equal(2, len(sections))
+		assert.Equal(uint8(0), sections[0][6])
+		assert.Equal(uint8(1), sections[1][6])
+	}
+
+	var sections [][]byte
+
+	psi := new(PSI)
+	ts := NewTS(0)
+	for p := makeCollectorPat(1, 300).Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if assert.NoError(err) {
+				b := make([]byte, len(psi.Payload()))
+				copy(b, psi.Payload())
+				sections = append(sections, b)
+			}
+		})
+	}
+
+	if !assert.Equal(2, len(sections)) {
+		return
+	}
+
+	feed := func(b []byte) {
+		psi := new(PSI)
+		psi.size = copy(psi.buffer[:], b)
+		if assert.NoError(psi.assembleCheck()) {
+			c.Collect(psi, onTable)
+		}
+	}
+
+	// second section first and repeated, table completed by the first section
+	feed(sections[1])
+	feed(sections[1])
+	assert.Equal(0, calls)
+	feed(sections[0])
+	assert.Equal(1, calls)
+}
+
+func TestTableCollector_EitSchedule(t *testing.T) {
+	assert := assert.New(t)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	text := make([]byte, 200)
+	for i := range text {
+		text[i] = 'a'
+	}
+	desc := (&Desc_4D{Language: "eng", EventName: "Event", Text: string(text)}).Encode()
+
+	// 3 sections in the first segment, 1 section in the second segment
+	eit := NewEit()
+	eit.SetTableID(0x50)
+	eit.SetServiceID(101)
+	for i := 0; i < 40; i++ {
+		eit.Items = append(eit.Items, makeTestEitItem(uint16(i), day.Add(time.Duration(i)*time.Minute), desc))
+	}
+	eit.Items = append(eit.Items, makeTestEitItem(40, day.Add(4*time.Hour), nil))
+	assert.NoError(eit.Finalize())
+
+	c := NewTableCollector()
+
+	calls := 0
+	collectTable(c, eit.Packetizer(), 18, func(psi *PSI, sections [][]byte) {
+		calls += 1
+		assert.Equal(uint16(101), psi.TableIDExtension)
+
+		decoded := new(EIT)
+		for _, b := range sections {
+			assert.NoError(decoded.ParseEitSection(b))
+		}
+		assert.True(decoded.Complete())
+		assert.Equal(41, len(decoded.Items))
+	})
+
+	assert.Equal(1, calls)
+}
+
+func TestTableCollector_EitScheduleGap(t *testing.T) {
+	assert := assert.New(t)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// events in the first and the fourth segments,
+	// second and third segments are empty
+	eit := NewEit()
+	eit.SetTableID(0x50)
+	eit.SetServiceID(101)
+	eit.Items = append(eit.Items, makeTestEitItem(1, day, nil))
+	eit.Items = append(eit.Items, makeTestEitItem(2, day.Add(10*time.Hour), nil))
+	assert.NoError(eit.Finalize())
+
+	c := NewTableCollector()
+
+	calls := 0
+	collectTable(c, eit.Packetizer(), 18, func(psi *PSI, sections [][]byte) {
+		calls += 1
+		assert.Equal(uint8(24), psi.LastSectionNumber)
+
+		numbers := []uint8{}
+		decoded := new(EIT)
+		for _, b := range sections {
+			numbers = append(numbers, b[6])
+			assert.NoError(decoded.ParseEitSection(b))
+		}
+		assert.Equal([]uint8{0, 8, 16, 24}, numbers)
+		assert.True(decoded.Complete())
+		assert.Equal(2, len(decoded.Items))
+	})
+
+	assert.Equal(1, calls)
+}
@@ -23,6 +23,11 @@ type psiSection interface {
 	sectionItem(i int) []byte
 }
 
+// psiShortSection should be implemented by sections without CRC_32 field
+type psiShortSection interface {
+	withoutChecksum()
+}
+
 // PsiPacketizer is a helper to splits PSI section into multiple TS packets.
 // If data more than fits into one section, it will be split into multiple sections.
 type PsiPacketizer struct {
@@ -32,13 +37,21 @@ type PsiPacketizer struct {
 	sectionFill int
 	skip        int
 	crc         uint32
+	crcSize     int // 0 for sections without checksum
 }
 
 func newPsiPacketizer(inner psiSection) *PsiPacketizer {
-	return &PsiPacketizer{
+	p := &PsiPacketizer{
 		inner:       inner,
 		sectionItem: -1,
+		crcSize:     crc32.Size,
 	}
+
+	if _, ok := inner.(psiShortSection); ok {
+		p.crcSize = 0
+	}
+
+	return p
 }
 
 func (p *PsiPacketizer) Next(ts TS) bool {
@@ -79,8 +92,8 @@ func (p *PsiPacketizer) Next(ts TS) bool {
 
 	for {
 		// current section finished. set checksum
-		if (p.sectionFill + crc32.Size) == p.sectionSize {
-			for p.skip < crc32.Size {
+		if (p.sectionFill + p.crcSize) == p.sectionSize {
+			for p.skip < p.crcSize {
 				shift := uint32(24 - (8 * p.skip))
 				ts[packetFill] = byte(p.crc >> shift)
 				packetFill += 1
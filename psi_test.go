@@ -52,8 +52,10 @@ func (t *TestData) clear() {
 	t.expectedError = false
 }
 
+// makePacket returns TS packet with data and stuffing bytes
 func makePacket(data []byte) TS {
 	p := NewTS(0)
+	copy(p[4:], NullTS[4:])
 	copy(p, data)
 	return p
 }
@@ -265,3 +267,95 @@ func Test_AssembleErrors(t *testing.T) {
 		test.section.Assemble(packet, test.onPSI)
 	})
 }
+
+func Test_AssemblePacked(t *testing.T) {
+	t.Run("several sections in one packet", func(t *testing.T) {
+		assert := assert.New(t)
+
+		data := []byte{0x47, 0x40, 0x00, 0x10, 0}
+		data = append(data, testPayload...)
+		data = append(data, testPayload...)
+		packet := makePacket(data)
+
+		psi := new(PSI)
+		calls := 0
+		psi.Assemble(packet, func(err error) {
+			calls += 1
+			if assert.NoError(err) {
+				assert.Equal(testPayload, psi.Payload())
+			}
+		})
+		assert.Equal(2, calls)
+	})
+
+	t.Run("new sections after the tail of the previous section", func(t *testing.T) {
+		assert := assert.New(t)
+
+		tdt := []byte{0x70, 0x70, 0x05, 0xC0, 0x79, 0x12, 0x45, 0x00}
+
+		data := []byte{0x47, 0x40, 0x00, 0x10, 163}
+		for i := 0; i < 163; i++ {
+			data = append(data, 0xFF)
+		}
+		data = append(data, testPayload[:20]...)
+		first := makePacket(data)
+
+		data = []byte{0x47, 0x40, 0x00, 0x11, 20}
+		data = append(data, testPayload[20:]...)
+		data = append(data, testPayload...)
+		data = append(data, tdt...)
+		second := makePacket(data)
+
+		psi := new(PSI)
+		var sections [][]byte
+		onPSI := func(err error) {
+			if assert.NoError(err) {
+				b := make([]byte, len(psi.Payload()))
+				copy(b, psi.Payload())
+				sections = append(sections, b)
+			}
+		}
+
+		psi.Assemble(first, onPSI)
+		assert.Equal(0, len(sections))
+
+		psi.Assemble(second, onPSI)
+		if assert.Equal(3, len(sections)) {
+			assert.Equal(testPayload, sections[0])
+			assert.Equal(testPayload, sections[1])
+			assert.Equal(tdt, sections[2])
+		}
+	})
+
+	t.Run("last section continues in the next packet", func(t *testing.T) {
+		assert := assert.New(t)
+
+		data := []byte{0x47, 0x40, 0x00, 0x10, 0}
+		for len(data)+len(testPayload) < PacketSize {
+			data = append(data, testPayload...)
+		}
+		count := (len(data) - 5) / len(testPayload)
+		skip := PacketSize - len(data)
+		data = append(data, testPayload[:skip]...)
+		first := makePacket(data)
+
+		data = []byte{0x47, 0x00, 0x00, 0x11}
+		data = append(data, testPayload[skip:]...)
+		second := makePacket(data)
+
+		psi := new(PSI)
+		calls := 0
+		onPSI := func(err error) {
+			calls += 1
+			if assert.NoError(err) {
+				assert.Equal(testPayload, psi.Payload())
+			}
+		}
+
+		psi.Assemble(first, onPSI)
+		assert.Equal(count, calls)
+
+		psi.Assemble(second, onPSI)
+		assert.Equal(count+1, calls)
+	})
+}
@@ -0,0 +1,306 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+// Common layout of the NIT and BAT sections:
+// header with table descriptors, transport_stream_loop_length,
+// and loop of transport streams with descriptors.
+const (
+	transportHeaderSize  = 10
+	transportMaximumSize = 1024
+	transportItemSize    = 6
+
+	// transport_stream_loop_length field size
+	transportLoopSize = 2
+
+	// Maximum size of the table descriptors to fit into the first section
+	transportDescriptorsMaximumSize = transportMaximumSize - transportHeaderSize - transportLoopSize - crc32.Size
+
+	// Maximum size of the transport stream descriptors to fit into the section
+	transportItemDescriptorsMaximumSize = transportDescriptorsMaximumSize - transportItemSize
+)
+
+// transportTable implements common part of the NIT and BAT.
+// Item headers are accessed with function that returns nil
+// if item with index i is not defined.
+type transportTable struct {
+	header []byte
+
+	section [transportHeaderSize + transportLoopSize]byte // header for next sections
+	loop    []byte                                        // table descriptors and loop length for first section
+}
+
+// transportItem implements common part of the NIT and BAT items
+type transportItem struct {
+	header []byte
+}
+
+var (
+	emptyTransportItem = []byte{
+		0x00, // transport_stream_id 1
+		0x00, // transport_stream_id 2
+		0x00, // original_network_id 1
+		0x00, // original_network_id 2
+		0xF0, // transport_descriptors_length 1
+		0x00, // transport_descriptors_length 2
+	}
+)
+
+// parse checks section and calls fn for each transport stream.
+// errFormat and name are used to report errors for the exact table.
+func (t *transportTable) parse(b []byte, errFormat error, name string, fn func(item transportItem)) error {
+	if len(b) < (transportHeaderSize + transportLoopSize + crc32.Size) {
+		return errFormat
+	}
+
+	next := transportHeaderSize
+	end := len(b) - crc32.Size
+
+	tableDescLen := binary.BigEndian.Uint16(b[8:]) & 0x0FFF
+	if tableDescLen > 0 {
+		next += int(tableDescLen)
+		if next+transportLoopSize > end {
+			return errFormat
+		}
+
+		tableDesc := Descriptors(b[transportHeaderSize:next])
+		if err := tableDesc.Check(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	// copy header only from first section
+	if b[6] == 0 {
+		t.header = make([]byte, next)
+		copy(t.header, b)
+	}
+
+	loopLen := binary.BigEndian.Uint16(b[next:]) & 0x0FFF
+	next += transportLoopSize
+	if next+int(loopLen) != end {
+		return errFormat
+	}
+
+	skip := next
+
+	for skip < end {
+		next += transportItemSize
+		if next > end {
+			return errFormat
+		}
+
+		descLen := binary.BigEndian.Uint16(b[skip+4:]) & 0x0FFF
+		if descLen > 0 {
+			next += int(descLen)
+			if next > end {
+				return errFormat
+			}
+
+			desc := Descriptors(b[skip+transportItemSize : next])
+			if err := desc.Check(); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+
+		item := transportItem{header: make([]byte, next-skip)}
+		copy(item.header, b[skip:])
+		fn(item)
+
+		skip = next
+	}
+
+	return nil
+}
+
+func (t *transportTable) Version() uint8 {
+	return (t.header[5] & 0x3E) >> 1
+}
+
+func (t *transportTable) SetVersion(version uint8) {
+	t.header[5] &^= 0x3E
+	t.header[5] |= (version << 1) & 0x3E
+}
+
+// Descriptors returns network descriptors for NIT or bouquet descriptors for BAT
+func (t *transportTable) Descriptors() Descriptors {
+	return Descriptors(t.header[transportHeaderSize:])
+}
+
+// AppendDescriptors appends descriptors to the table descriptors.
+// Returns ErrDescriptorSize if descriptors do not fit into the section.
+func (t *transportTable) AppendDescriptors(desc Descriptors) error {
+	if len(desc) == 0 {
+		return nil
+	}
+
+	ds := len(t.header) - transportHeaderSize + len(desc)
+	if ds > transportDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+	t.header = append(t.header, desc...)
+
+	binary.BigEndian.PutUint16(t.header[8:], 0xF000|uint16(ds))
+	return nil
+}
+
+// SetDescriptors replaces table descriptors
+func (t *transportTable) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > transportDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+
+	// new buffer for header: desc could be a part of the current header
+	t.header = append(t.header[:transportHeaderSize:transportHeaderSize], desc...)
+
+	binary.BigEndian.PutUint16(t.header[8:], 0xF000|uint16(len(desc)))
+	return nil
+}
+
+// finalize calculates LastSectionNumber
+func (t *transportTable) finalize(item func(i int) []byte) {
+	t.header[6] = 0
+	t.header[7] = 0
+
+	size := len(t.header) + transportLoopSize
+	remain := transportMaximumSize - size - crc32.Size
+
+	for i := 0; item(i) != nil; i++ {
+		is := len(item(i))
+		if is > remain {
+			remain = transportMaximumSize - transportHeaderSize - transportLoopSize - crc32.Size
+			t.header[7] += 1
+		}
+		remain -= is
+	}
+}
+
+func (t *transportTable) size(i int, item func(i int) []byte) int {
+	if i != -1 && item(i) == nil {
+		return 0
+	}
+
+	size := transportLoopSize + crc32.Size
+
+	if i == -1 {
+		size += len(t.header)
+		i = 0
+	} else {
+		size += transportHeaderSize
+	}
+
+	loop := 0
+
+	for header := item(i); header != nil; header = item(i) {
+		is := len(header)
+		if (size + is) > transportMaximumSize {
+			break
+		} else {
+			size += is
+			loop += is
+			i += 1
+		}
+	}
+
+	// transport_stream_loop_length for the current section
+	binary.BigEndian.PutUint16(t.section[transportHeaderSize:], 0xF000|uint16(loop))
+
+	return size
+}
+
+func (t *transportTable) sectionHeader(i int) []byte {
+	if i == -1 {
+		t.header[6] = 0
+		s := uint16(len(t.header) - transportHeaderSize)
+		t.header[8] = 0xF0 | byte(s>>8)
+		t.header[9] = byte(s)
+
+		return t.header[:transportHeaderSize]
+	}
+
+	t.header[6] += 1
+	copy(t.section[:], t.header[:transportHeaderSize])
+	t.section[8] = 0xF0
+	t.section[9] = 0x00
+
+	return t.section[:]
+}
+
+func (t *transportTable) item(i int, item func(i int) []byte) []byte {
+	if i == -1 {
+		// table descriptors and transport_stream_loop_length
+		t.loop = append(t.loop[:0], t.header[transportHeaderSize:]...)
+		t.loop = append(t.loop, t.section[transportHeaderSize:]...)
+		return t.loop
+	}
+
+	return item(i)
+}
+
+func newTransportItem() transportItem {
+	item := transportItem{header: make([]byte, len(emptyTransportItem))}
+	copy(item.header, emptyTransportItem)
+
+	return item
+}
+
+func (t *transportItem) TSID() uint16 {
+	return binary.BigEndian.Uint16(t.header[0:])
+}
+
+func (t *transportItem) SetTSID(tsid uint16) {
+	binary.BigEndian.PutUint16(t.header[0:], tsid)
+}
+
+func (t *transportItem) ONID() uint16 {
+	return binary.BigEndian.Uint16(t.header[2:])
+}
+
+func (t *transportItem) SetONID(onid uint16) {
+	binary.BigEndian.PutUint16(t.header[2:], onid)
+}
+
+func (t *transportItem) Descriptors() Descriptors {
+	return Descriptors(t.header[transportItemSize:])
+}
+
+// AppendDescriptors appends descriptors to the transport stream descriptors.
+// Returns ErrDescriptorSize if descriptors do not fit into the section.
+func (t *transportItem) AppendDescriptors(desc Descriptors) error {
+	if len(desc) == 0 {
+		return nil
+	}
+
+	ds := len(t.header) - transportItemSize + len(desc)
+	if ds > transportItemDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+	t.header = append(t.header, desc...)
+
+	binary.BigEndian.PutUint16(t.header[4:], 0xF000|uint16(ds))
+	return nil
+}
+
+// SetDescriptors replaces transport stream descriptors
+func (t *transportItem) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > transportItemDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+
+	t.header = append(t.header[:transportItemSize:transportItemSize], desc...)
+
+	binary.BigEndian.PutUint16(t.header[4:], 0xF000|uint16(len(desc)))
+	return nil
+}
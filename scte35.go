@@ -0,0 +1,430 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+// SCTE 35 : Digital Program Insertion Cueing Message
+
+const (
+	// Fields from table_id to splice_command_type
+	SpliceInfoHeaderSize = 14
+
+	// Maximum size of the splice_info_section including PsiHeaderSize
+	SpliceInfoMaximumSize = 4096
+
+	// splice_command_length for legacy sections with undefined length
+	spliceCommandLengthUndefined = 0x0FFF
+)
+
+// Splice command types
+const (
+	SpliceNullType           uint8 = 0x00
+	SpliceScheduleType       uint8 = 0x04
+	SpliceInsertType         uint8 = 0x05
+	TimeSignalType           uint8 = 0x06
+	BandwidthReservationType uint8 = 0x07
+	PrivateCommandType       uint8 = 0xFF
+)
+
+const (
+	// splice_descriptor_tag, descriptor_length, and identifier
+	spliceDescriptorHeaderSize = 6
+
+	// identifier of the SCTE 35 splice descriptors: "CUEI"
+	spliceIdentifierCUEI uint32 = 0x43554549
+)
+
+// SpliceInfo is a splice_info_section
+type SpliceInfo struct {
+	SAPType             uint8 // 2-bit sap_type. 3 if not specified
+	ProtocolVersion     uint8
+	Encrypted           bool // encrypted_packet
+	EncryptionAlgorithm uint8
+	PTSAdjustment       Timestamp
+	CWIndex             uint8
+	Tier                uint16 // 12-bit tier. 0xFFF if not used
+
+	Command     SpliceCommand
+	Descriptors []SpliceDescriptor
+
+	// Encrypted part of the section as is: splice command, descriptors,
+	// alignment stuffing, and E_CRC_32. Used only if Encrypted is true
+	EncryptedData          []byte
+	EncryptedCommandLength uint16
+}
+
+// SpliceCommand is a command of the splice_info_section
+type SpliceCommand interface {
+	SpliceCommandType() uint8
+	decode(r *spliceReader)
+	encode(b []byte) []byte
+}
+
+// SpliceDescriptor is a descriptor of the splice_info_section
+type SpliceDescriptor interface {
+	SpliceDescriptorTag() uint8
+	decode(r *spliceReader) // reads identifier and descriptor data
+	encode(b []byte) []byte // writes identifier and descriptor data
+}
+
+var (
+	ErrSpliceFormat = errors.New("scte35: invalid format")
+	ErrSpliceSize   = errors.New("scte35: size limit")
+)
+
+// NewSpliceInfo returns a new SpliceInfo with default values and splice_null command
+func NewSpliceInfo() *SpliceInfo {
+	return &SpliceInfo{
+		SAPType: 3,
+		Tier:    0x0FFF,
+		Command: &SpliceNull{},
+	}
+}
+
+// spliceReader reads fields with bounds checking.
+// On out of range sets err and returns zero values.
+type spliceReader struct {
+	b   []byte
+	pos int
+	err bool
+}
+
+func (r *spliceReader) remain() int {
+	return len(r.b) - r.pos
+}
+
+func (r *spliceReader) bytes(n int) []byte {
+	if r.err || n < 0 || r.pos+n > len(r.b) {
+		r.err = true
+		return nil
+	}
+
+	b := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *spliceReader) u8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *spliceReader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *spliceReader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// u40 reads 5 bytes: 7 high bits and 33-bit value
+func (r *spliceReader) u40() (uint8, Timestamp) {
+	if b := r.bytes(5); b != nil {
+		v := uint64(b[0]&0x01)<<32 | uint64(binary.BigEndian.Uint32(b[1:]))
+		return b[0] >> 1, Timestamp(v)
+	}
+	return 0, 0
+}
+
+// put40 appends 7 high bits and 33-bit value
+func put40(b []byte, flags uint8, value Timestamp) []byte {
+	b = append(b, (flags<<1)|byte((value>>32)&0x01))
+	return binary.BigEndian.AppendUint32(b, uint32(value))
+}
+
+// readSpliceTime reads splice_time().
+// Returns NonTimestamp if time_specified_flag is not set.
+func (r *spliceReader) spliceTime() Timestamp {
+	if r.remain() < 1 {
+		r.err = true
+		return NonTimestamp
+	}
+
+	if (r.b[r.pos] & 0x80) == 0 {
+		r.pos += 1
+		return NonTimestamp
+	}
+
+	_, v := r.u40()
+	return v
+}
+
+// putSpliceTime appends splice_time()
+func putSpliceTime(b []byte, value Timestamp) []byte {
+	if value == NonTimestamp {
+		return append(b, 0x7F)
+	}
+
+	return put40(b, 0x7F, value)
+}
+
+// Decode parses splice_info_section. b should contain complete section with CRC
+func (s *SpliceInfo) Decode(b []byte) error {
+	*s = SpliceInfo{}
+
+	if len(b) < (SpliceInfoHeaderSize+2+crc32.Size) || b[0] != 0xFC {
+		return ErrSpliceFormat
+	}
+
+	size := PsiHeaderSize + int(binary.BigEndian.Uint16(b[1:])&0x0FFF)
+	if size > len(b) || size < (SpliceInfoHeaderSize+2+crc32.Size) {
+		return ErrSpliceFormat
+	}
+
+	s.SAPType = (b[1] >> 4) & 0x03
+	s.ProtocolVersion = b[3]
+	s.Encrypted = (b[4] & 0x80) != 0
+	s.EncryptionAlgorithm = (b[4] >> 1) & 0x3F
+	s.PTSAdjustment = Timestamp(uint64(b[4]&0x01)<<32 | uint64(binary.BigEndian.Uint32(b[5:])))
+	s.CWIndex = b[9]
+	s.Tier = binary.BigEndian.Uint16(b[10:]) >> 4
+	commandLength := int(binary.BigEndian.Uint16(b[11:]) & 0x0FFF)
+
+	end := size - crc32.Size
+
+	if s.Encrypted {
+		s.EncryptedCommandLength = uint16(commandLength)
+		s.EncryptedData = make([]byte, end-(SpliceInfoHeaderSize-1))
+		copy(s.EncryptedData, b[SpliceInfoHeaderSize-1:end])
+		return nil
+	}
+
+	commandType := b[13]
+
+	var r *spliceReader
+	if commandLength == spliceCommandLengthUndefined {
+		r = &spliceReader{b: b[SpliceInfoHeaderSize:end]}
+	} else {
+		next := SpliceInfoHeaderSize + commandLength
+		if next > end {
+			return ErrSpliceFormat
+		}
+		r = &spliceReader{b: b[SpliceInfoHeaderSize:next]}
+	}
+
+	switch commandType {
+	case SpliceNullType:
+		s.Command = &SpliceNull{}
+	case SpliceScheduleType:
+		s.Command = &SpliceSchedule{}
+	case SpliceInsertType:
+		s.Command = &SpliceInsert{}
+	case TimeSignalType:
+		s.Command = &TimeSignal{}
+	case BandwidthReservationType:
+		s.Command = &BandwidthReservation{}
+	case PrivateCommandType:
+		if commandLength == spliceCommandLengthUndefined {
+			return ErrSpliceFormat
+		}
+		s.Command = &PrivateCommand{}
+	default:
+		return ErrSpliceFormat
+	}
+
+	s.Command.decode(r)
+	if r.err {
+		return ErrSpliceFormat
+	}
+
+	// descriptor_loop_length follows splice_command_length bytes.
+	// Commands could have extension bytes after known fields
+	skip := SpliceInfoHeaderSize + commandLength
+	if commandLength == spliceCommandLengthUndefined {
+		skip = SpliceInfoHeaderSize + r.pos
+	}
+	if skip+2 > end {
+		return ErrSpliceFormat
+	}
+
+	next := skip + 2 + int(binary.BigEndian.Uint16(b[skip:]))
+	if next > end {
+		return ErrSpliceFormat
+	}
+
+	desc, err := decodeSpliceDescriptors(b[skip+2 : next])
+	if err != nil {
+		return err
+	}
+	s.Descriptors = desc
+
+	return nil
+}
+
+func decodeSpliceDescriptors(b []byte) ([]SpliceDescriptor, error) {
+	var list []SpliceDescriptor
+
+	for len(b) != 0 {
+		if len(b) < 2 {
+			return nil, ErrSpliceFormat
+		}
+
+		next := 2 + int(b[1])
+		if next > len(b) || next < spliceDescriptorHeaderSize {
+			return nil, ErrSpliceFormat
+		}
+
+		var d SpliceDescriptor
+
+		tag := b[0]
+		identifier := binary.BigEndian.Uint32(b[2:])
+
+		if identifier != spliceIdentifierCUEI {
+			d = &SpliceRawDescriptor{Tag: tag}
+		} else {
+			switch tag {
+			case SpliceAvailDescriptorTag:
+				d = &SpliceAvailDescriptor{}
+			case SpliceDTMFDescriptorTag:
+				d = &SpliceDTMFDescriptor{}
+			case SegmentationDescriptorTag:
+				d = &SegmentationDescriptor{}
+			case SpliceTimeDescriptorTag:
+				d = &SpliceTimeDescriptor{}
+			case SpliceAudioDescriptorTag:
+				d = &SpliceAudioDescriptor{}
+			default:
+				d = &SpliceRawDescriptor{Tag: tag}
+			}
+		}
+
+		r := &spliceReader{b: b[2:next]}
+		d.decode(r)
+		if r.err {
+			return nil, ErrSpliceFormat
+		}
+
+		list = append(list, d)
+		b = b[next:]
+	}
+
+	return list, nil
+}
+
+// encode returns section without CRC_32
+func (s *SpliceInfo) encode() ([]byte, error) {
+	b := make([]byte, SpliceInfoHeaderSize-1, 256)
+
+	b[0] = 0xFC
+	b[1] = (s.SAPType & 0x03) << 4
+	b[3] = s.ProtocolVersion
+	b[4] = ((s.EncryptionAlgorithm & 0x3F) << 1) | byte((s.PTSAdjustment>>32)&0x01)
+	if s.Encrypted {
+		b[4] |= 0x80
+	}
+	binary.BigEndian.PutUint32(b[5:], uint32(s.PTSAdjustment))
+	b[9] = s.CWIndex
+	b[10] = byte(s.Tier >> 4)
+	b[11] = byte(s.Tier << 4)
+
+	var commandLength int
+
+	if s.Encrypted {
+		commandLength = int(s.EncryptedCommandLength)
+		b = append(b, s.EncryptedData...)
+	} else {
+		command := s.Command
+		if command == nil {
+			command = &SpliceNull{}
+		}
+
+		b = append(b, command.SpliceCommandType())
+		b = command.encode(b)
+		commandLength = len(b) - SpliceInfoHeaderSize
+
+		skip := len(b)
+		b = append(b, 0x00, 0x00) // descriptor_loop_length
+
+		for _, d := range s.Descriptors {
+			ds := len(b)
+			b = append(b, d.SpliceDescriptorTag(), 0x00)
+			b = d.encode(b)
+
+			dl := len(b) - ds - 2
+			if dl > 0xFF {
+				return nil, ErrSpliceSize
+			}
+			b[ds+1] = byte(dl)
+		}
+
+		dl := len(b) - skip - 2
+		if dl > 0xFFFF {
+			return nil, ErrSpliceSize
+		}
+		binary.BigEndian.PutUint16(b[skip:], uint16(dl))
+	}
+
+	if commandLength > 0x0FFF || (len(b)+crc32.Size) > SpliceInfoMaximumSize {
+		return nil, ErrSpliceSize
+	}
+
+	b[11] |= byte(commandLength >> 8)
+	b[12] = byte(commandLength)
+
+	length := len(b) + crc32.Size - PsiHeaderSize
+	b[1] |= byte(length >> 8)
+	b[2] = byte(length)
+
+	return b, nil
+}
+
+// Encode returns splice_info_section with CRC_32
+func (s *SpliceInfo) Encode() ([]byte, error) {
+	b, err := s.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	crc := crc32.Checksum(0xFFFFFFFF, b)
+	return binary.BigEndian.AppendUint32(b, crc), nil
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from SpliceInfo
+func (s *SpliceInfo) Packetizer() (*PsiPacketizer, error) {
+	b, err := s.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return newPsiPacketizer(spliceSection(b)), nil
+}
+
+// spliceSection is an encoded splice_info_section without CRC_32.
+// Fields after the splice_command_type are packetized as an item
+// because section header should fit into the first TS packet
+type spliceSection []byte
+
+func (s spliceSection) sectionSize(i int) int {
+	if i != -1 {
+		return 0
+	}
+
+	return len(s) + crc32.Size
+}
+
+func (s spliceSection) sectionHeader(i int) []byte {
+	return s[:SpliceInfoHeaderSize]
+}
+
+func (s spliceSection) sectionItem(i int) []byte {
+	switch i {
+	case -1:
+		return []byte{}
+	case 0:
+		return s[SpliceInfoHeaderSize:]
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,316 @@
+package mpegts
+
+import (
+	"encoding/binary"
+)
+
+// SpliceNull is a splice_null command. Used for heartbeat messages
+type SpliceNull struct{}
+
+// SpliceSchedule is a splice_schedule command
+type SpliceSchedule struct {
+	Events []SpliceScheduleEvent
+}
+
+type SpliceScheduleEvent struct {
+	EventID       uint32
+	Cancel        bool // splice_event_cancel_indicator
+	OutOfNetwork  bool // out_of_network_indicator
+	ProgramSplice bool // program_splice_flag
+
+	// utc_splice_time for the program splice mode.
+	// Seconds since 1980-01-06 00:00:00 UTC
+	UTCSpliceTime uint32
+
+	// Components for the component splice mode
+	Components []SpliceScheduleComponent
+
+	BreakDuration *BreakDuration
+
+	UniqueProgramID uint16
+	AvailNum        uint8
+	AvailsExpected  uint8
+}
+
+type SpliceScheduleComponent struct {
+	Tag           uint8
+	UTCSpliceTime uint32
+}
+
+// SpliceInsert is a splice_insert command
+type SpliceInsert struct {
+	EventID         uint32
+	Cancel          bool // splice_event_cancel_indicator
+	OutOfNetwork    bool // out_of_network_indicator
+	ProgramSplice   bool // program_splice_flag
+	SpliceImmediate bool // splice_immediate_flag
+
+	// splice_time for the program splice mode.
+	// NonTimestamp if time is not specified
+	SpliceTime Timestamp
+
+	// Components for the component splice mode
+	Components []SpliceInsertComponent
+
+	BreakDuration *BreakDuration
+
+	UniqueProgramID uint16
+	AvailNum        uint8
+	AvailsExpected  uint8
+}
+
+type SpliceInsertComponent struct {
+	Tag uint8
+
+	// NonTimestamp if time is not specified or splice is immediate
+	SpliceTime Timestamp
+}
+
+// BreakDuration is a break_duration structure
+type BreakDuration struct {
+	AutoReturn bool
+	Duration   Timestamp // in 90kHz units
+}
+
+// TimeSignal is a time_signal command
+type TimeSignal struct {
+	// NonTimestamp if time is not specified
+	SpliceTime Timestamp
+}
+
+// BandwidthReservation is a bandwidth_reservation command
+type BandwidthReservation struct{}
+
+// PrivateCommand is a private_command
+type PrivateCommand struct {
+	Identifier uint32
+	Data       []byte
+}
+
+func (c *SpliceNull) SpliceCommandType() uint8 { return SpliceNullType }
+
+func (c *SpliceNull) decode(r *spliceReader) {}
+
+func (c *SpliceNull) encode(b []byte) []byte { return b }
+
+func (c *BandwidthReservation) SpliceCommandType() uint8 { return BandwidthReservationType }
+
+func (c *BandwidthReservation) decode(r *spliceReader) {}
+
+func (c *BandwidthReservation) encode(b []byte) []byte { return b }
+
+func (c *TimeSignal) SpliceCommandType() uint8 { return TimeSignalType }
+
+func (c *TimeSignal) decode(r *spliceReader) {
+	c.SpliceTime = r.spliceTime()
+}
+
+func (c *TimeSignal) encode(b []byte) []byte {
+	return putSpliceTime(b, c.SpliceTime)
+}
+
+func (c *PrivateCommand) SpliceCommandType() uint8 { return PrivateCommandType }
+
+func (c *PrivateCommand) decode(r *spliceReader) {
+	c.Identifier = r.u32()
+	c.Data = append([]byte(nil), r.bytes(r.remain())...)
+}
+
+func (c *PrivateCommand) encode(b []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, c.Identifier)
+	return append(b, c.Data...)
+}
+
+func (d *BreakDuration) decode(r *spliceReader) {
+	flags, v := r.u40()
+	d.AutoReturn = (flags & 0x40) != 0
+	d.Duration = v
+}
+
+func (d *BreakDuration) encode(b []byte) []byte {
+	flags := uint8(0x3F)
+	if d.AutoReturn {
+		flags |= 0x40
+	}
+
+	return put40(b, flags, d.Duration)
+}
+
+func (c *SpliceSchedule) SpliceCommandType() uint8 { return SpliceScheduleType }
+
+func (c *SpliceSchedule) decode(r *spliceReader) {
+	count := int(r.u8())
+
+	c.Events = nil
+	for i := 0; i < count && !r.err; i++ {
+		e := SpliceScheduleEvent{}
+		e.EventID = r.u32()
+		e.Cancel = (r.u8() & 0x80) != 0
+
+		if !e.Cancel {
+			flags := r.u8()
+			e.OutOfNetwork = (flags & 0x80) != 0
+			e.ProgramSplice = (flags & 0x40) != 0
+			hasDuration := (flags & 0x20) != 0
+
+			if e.ProgramSplice {
+				e.UTCSpliceTime = r.u32()
+			} else {
+				n := int(r.u8())
+				for j := 0; j < n && !r.err; j++ {
+					e.Components = append(e.Components, SpliceScheduleComponent{
+						Tag:           r.u8(),
+						UTCSpliceTime: r.u32(),
+					})
+				}
+			}
+
+			if hasDuration {
+				e.BreakDuration = new(BreakDuration)
+				e.BreakDuration.decode(r)
+			}
+
+			e.UniqueProgramID = r.u16()
+			e.AvailNum = r.u8()
+			e.AvailsExpected = r.u8()
+		}
+
+		c.Events = append(c.Events, e)
+	}
+}
+
+func (c *SpliceSchedule) encode(b []byte) []byte {
+	b = append(b, byte(len(c.Events)))
+
+	for _, e := range c.Events {
+		b = binary.BigEndian.AppendUint32(b, e.EventID)
+
+		if e.Cancel {
+			b = append(b, 0xFF)
+			continue
+		}
+		b = append(b, 0x7F)
+
+		flags := uint8(0x1F)
+		if e.OutOfNetwork {
+			flags |= 0x80
+		}
+		if e.ProgramSplice {
+			flags |= 0x40
+		}
+		if e.BreakDuration != nil {
+			flags |= 0x20
+		}
+		b = append(b, flags)
+
+		if e.ProgramSplice {
+			b = binary.BigEndian.AppendUint32(b, e.UTCSpliceTime)
+		} else {
+			b = append(b, byte(len(e.Components)))
+			for _, c := range e.Components {
+				b = append(b, c.Tag)
+				b = binary.BigEndian.AppendUint32(b, c.UTCSpliceTime)
+			}
+		}
+
+		if e.BreakDuration != nil {
+			b = e.BreakDuration.encode(b)
+		}
+
+		b = binary.BigEndian.AppendUint16(b, e.UniqueProgramID)
+		b = append(b, e.AvailNum, e.AvailsExpected)
+	}
+
+	return b
+}
+
+func (c *SpliceInsert) SpliceCommandType() uint8 { return SpliceInsertType }
+
+func (c *SpliceInsert) decode(r *spliceReader) {
+	*c = SpliceInsert{SpliceTime: NonTimestamp}
+
+	c.EventID = r.u32()
+	c.Cancel = (r.u8() & 0x80) != 0
+
+	if c.Cancel {
+		return
+	}
+
+	flags := r.u8()
+	c.OutOfNetwork = (flags & 0x80) != 0
+	c.ProgramSplice = (flags & 0x40) != 0
+	hasDuration := (flags & 0x20) != 0
+	c.SpliceImmediate = (flags & 0x10) != 0
+
+	if c.ProgramSplice {
+		if !c.SpliceImmediate {
+			c.SpliceTime = r.spliceTime()
+		}
+	} else {
+		n := int(r.u8())
+		for i := 0; i < n && !r.err; i++ {
+			component := SpliceInsertComponent{
+				Tag:        r.u8(),
+				SpliceTime: NonTimestamp,
+			}
+			if !c.SpliceImmediate {
+				component.SpliceTime = r.spliceTime()
+			}
+			c.Components = append(c.Components, component)
+		}
+	}
+
+	if hasDuration {
+		c.BreakDuration = new(BreakDuration)
+		c.BreakDuration.decode(r)
+	}
+
+	c.UniqueProgramID = r.u16()
+	c.AvailNum = r.u8()
+	c.AvailsExpected = r.u8()
+}
+
+func (c *SpliceInsert) encode(b []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, c.EventID)
+
+	if c.Cancel {
+		return append(b, 0xFF)
+	}
+	b = append(b, 0x7F)
+
+	flags := uint8(0x0F)
+	if c.OutOfNetwork {
+		flags |= 0x80
+	}
+	if c.ProgramSplice {
+		flags |= 0x40
+	}
+	if c.BreakDuration != nil {
+		flags |= 0x20
+	}
+	if c.SpliceImmediate {
+		flags |= 0x10
+	}
+	b = append(b, flags)
+
+	if c.ProgramSplice {
+		if !c.SpliceImmediate {
+			b = putSpliceTime(b, c.SpliceTime)
+		}
+	} else {
+		b = append(b, byte(len(c.Components)))
+		for _, component := range c.Components {
+			b = append(b, component.Tag)
+			if !c.SpliceImmediate {
+				b = putSpliceTime(b, component.SpliceTime)
+			}
+		}
+	}
+
+	if c.BreakDuration != nil {
+		b = c.BreakDuration.encode(b)
+	}
+
+	b = binary.BigEndian.AppendUint16(b, c.UniqueProgramID)
+	return append(b, c.AvailNum, c.AvailsExpected)
+}
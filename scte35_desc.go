@@ -0,0 +1,314 @@
+package mpegts
+
+import (
+	"encoding/binary"
+)
+
+// Splice descriptor tags
+const (
+	SpliceAvailDescriptorTag  uint8 = 0x00
+	SpliceDTMFDescriptorTag   uint8 = 0x01
+	SegmentationDescriptorTag uint8 = 0x02
+	SpliceTimeDescriptorTag   uint8 = 0x03
+	SpliceAudioDescriptorTag  uint8 = 0x04
+)
+
+// SpliceAvailDescriptor is an avail_descriptor
+type SpliceAvailDescriptor struct {
+	ProviderAvailID uint32
+}
+
+// SpliceDTMFDescriptor is a DTMF_descriptor
+type SpliceDTMFDescriptor struct {
+	Preroll uint8 // in 0.1 second units
+	Chars   string
+}
+
+// SegmentationDescriptor is a segmentation_descriptor
+type SegmentationDescriptor struct {
+	EventID uint32
+	Cancel  bool // segmentation_event_cancel_indicator
+
+	ProgramSegmentation bool // program_segmentation_flag
+
+	// Fields below are defined only if DeliveryNotRestricted is false
+	DeliveryNotRestricted bool
+	WebDeliveryAllowed    bool
+	NoRegionalBlackout    bool
+	ArchiveAllowed        bool
+	DeviceRestrictions    uint8
+
+	// Components for the component segmentation mode
+	Components []SegmentationComponent
+
+	HasDuration bool
+	Duration    uint64 // 40-bit duration in 90kHz units
+
+	UPIDType uint8
+	UPID     []byte
+
+	TypeID           uint8 // segmentation_type_id
+	SegmentNum       uint8
+	SegmentsExpected uint8
+
+	// Defined for segmentation_type_id 0x34, 0x36, 0x38, and 0x3A
+	SubSegmentNum       uint8
+	SubSegmentsExpected uint8
+}
+
+type SegmentationComponent struct {
+	Tag       uint8
+	PTSOffset Timestamp
+}
+
+// SpliceTimeDescriptor is a time_descriptor
+type SpliceTimeDescriptor struct {
+	TAISeconds     uint64 // 48-bit
+	TAINanoseconds uint32
+	UTCOffset      uint16
+}
+
+// SpliceAudioDescriptor is an audio_descriptor
+type SpliceAudioDescriptor struct {
+	Components []SpliceAudioComponent
+}
+
+type SpliceAudioComponent struct {
+	Tag              uint8
+	Language         string // ISO 639-2 language code
+	BitStreamMode    uint8
+	NumChannels      uint8
+	FullServiceAudio bool
+}
+
+// SpliceRawDescriptor is a descriptor with unknown tag or private identifier
+type SpliceRawDescriptor struct {
+	Tag        uint8
+	Identifier uint32
+	Data       []byte
+}
+
+func putIdentifier(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, spliceIdentifierCUEI)
+}
+
+func (d *SpliceAvailDescriptor) SpliceDescriptorTag() uint8 { return SpliceAvailDescriptorTag }
+
+func (d *SpliceAvailDescriptor) decode(r *spliceReader) {
+	r.u32()
+	d.ProviderAvailID = r.u32()
+}
+
+func (d *SpliceAvailDescriptor) encode(b []byte) []byte {
+	b = putIdentifier(b)
+	return binary.BigEndian.AppendUint32(b, d.ProviderAvailID)
+}
+
+func (d *SpliceDTMFDescriptor) SpliceDescriptorTag() uint8 { return SpliceDTMFDescriptorTag }
+
+func (d *SpliceDTMFDescriptor) decode(r *spliceReader) {
+	r.u32()
+	d.Preroll = r.u8()
+	count := int(r.u8() >> 5)
+	d.Chars = string(r.bytes(count))
+}
+
+func (d *SpliceDTMFDescriptor) encode(b []byte) []byte {
+	b = putIdentifier(b)
+	chars := d.Chars
+	if len(chars) > 7 {
+		chars = chars[:7]
+	}
+	b = append(b, d.Preroll, byte(len(chars)<<5)|0x1F)
+	return append(b, chars...)
+}
+
+// hasSubSegments checks segmentation_type_id with sub_segment fields
+func (d *SegmentationDescriptor) hasSubSegments() bool {
+	switch d.TypeID {
+	case 0x34, 0x36, 0x38, 0x3A:
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *SegmentationDescriptor) SpliceDescriptorTag() uint8 { return SegmentationDescriptorTag }
+
+func (d *SegmentationDescriptor) decode(r *spliceReader) {
+	*d = SegmentationDescriptor{}
+
+	r.u32()
+	d.EventID = r.u32()
+	d.Cancel = (r.u8() & 0x80) != 0
+
+	if d.Cancel {
+		return
+	}
+
+	flags := r.u8()
+	d.ProgramSegmentation = (flags & 0x80) != 0
+	d.HasDuration = (flags & 0x40) != 0
+	d.DeliveryNotRestricted = (flags & 0x20) != 0
+	if !d.DeliveryNotRestricted {
+		d.WebDeliveryAllowed = (flags & 0x10) != 0
+		d.NoRegionalBlackout = (flags & 0x08) != 0
+		d.ArchiveAllowed = (flags & 0x04) != 0
+		d.DeviceRestrictions = flags & 0x03
+	}
+
+	if !d.ProgramSegmentation {
+		n := int(r.u8())
+		for i := 0; i < n && !r.err; i++ {
+			tag := r.u8()
+			_, offset := r.u40()
+			d.Components = append(d.Components, SegmentationComponent{
+				Tag:       tag,
+				PTSOffset: offset,
+			})
+		}
+	}
+
+	if d.HasDuration {
+		if b := r.bytes(5); b != nil {
+			d.Duration = uint64(b[0])<<32 | uint64(binary.BigEndian.Uint32(b[1:]))
+		}
+	}
+
+	d.UPIDType = r.u8()
+	d.UPID = append([]byte(nil), r.bytes(int(r.u8()))...)
+	d.TypeID = r.u8()
+	d.SegmentNum = r.u8()
+	d.SegmentsExpected = r.u8()
+
+	// sub_segment fields are optional in the previous versions of the standard
+	if d.hasSubSegments() && r.remain() >= 2 {
+		d.SubSegmentNum = r.u8()
+		d.SubSegmentsExpected = r.u8()
+	}
+}
+
+func (d *SegmentationDescriptor) encode(b []byte) []byte {
+	b = putIdentifier(b)
+	b = binary.BigEndian.AppendUint32(b, d.EventID)
+
+	if d.Cancel {
+		return append(b, 0xFF)
+	}
+	b = append(b, 0x7F)
+
+	flags := uint8(0)
+	if d.ProgramSegmentation {
+		flags |= 0x80
+	}
+	if d.HasDuration {
+		flags |= 0x40
+	}
+	if d.DeliveryNotRestricted {
+		flags |= 0x20 | 0x1F
+	} else {
+		if d.WebDeliveryAllowed {
+			flags |= 0x10
+		}
+		if d.NoRegionalBlackout {
+			flags |= 0x08
+		}
+		if d.ArchiveAllowed {
+			flags |= 0x04
+		}
+		flags |= d.DeviceRestrictions & 0x03
+	}
+	b = append(b, flags)
+
+	if !d.ProgramSegmentation {
+		b = append(b, byte(len(d.Components)))
+		for _, c := range d.Components {
+			b = append(b, c.Tag)
+			b = put40(b, 0x7F, c.PTSOffset)
+		}
+	}
+
+	if d.HasDuration {
+		b = append(b, byte(d.Duration>>32))
+		b = binary.BigEndian.AppendUint32(b, uint32(d.Duration))
+	}
+
+	b = append(b, d.UPIDType, byte(len(d.UPID)))
+	b = append(b, d.UPID...)
+	b = append(b, d.TypeID, d.SegmentNum, d.SegmentsExpected)
+
+	if d.hasSubSegments() {
+		b = append(b, d.SubSegmentNum, d.SubSegmentsExpected)
+	}
+
+	return b
+}
+
+func (d *SpliceTimeDescriptor) SpliceDescriptorTag() uint8 { return SpliceTimeDescriptorTag }
+
+func (d *SpliceTimeDescriptor) decode(r *spliceReader) {
+	r.u32()
+	hi := uint64(r.u16())
+	d.TAISeconds = hi<<32 | uint64(r.u32())
+	d.TAINanoseconds = r.u32()
+	d.UTCOffset = r.u16()
+}
+
+func (d *SpliceTimeDescriptor) encode(b []byte) []byte {
+	b = putIdentifier(b)
+	b = binary.BigEndian.AppendUint16(b, uint16(d.TAISeconds>>32))
+	b = binary.BigEndian.AppendUint32(b, uint32(d.TAISeconds))
+	b = binary.BigEndian.AppendUint32(b, d.TAINanoseconds)
+	return binary.BigEndian.AppendUint16(b, d.UTCOffset)
+}
+
+func (d *SpliceAudioDescriptor) SpliceDescriptorTag() uint8 { return SpliceAudioDescriptorTag }
+
+func (d *SpliceAudioDescriptor) decode(r *spliceReader) {
+	r.u32()
+	count := int(r.u8() >> 4)
+
+	d.Components = nil
+	for i := 0; i < count && !r.err; i++ {
+		tag := r.u8()
+		lang := string(r.bytes(3))
+		flags := r.u8()
+		d.Components = append(d.Components, SpliceAudioComponent{
+			Tag:              tag,
+			Language:         lang,
+			BitStreamMode:    flags >> 5,
+			NumChannels:      (flags >> 1) & 0x0F,
+			FullServiceAudio: (flags & 0x01) != 0,
+		})
+	}
+}
+
+func (d *SpliceAudioDescriptor) encode(b []byte) []byte {
+	b = putIdentifier(b)
+	b = append(b, byte(len(d.Components)<<4)|0x0F)
+
+	for _, c := range d.Components {
+		b = append(b, c.Tag, ' ', ' ', ' ')
+		copy(b[len(b)-3:], c.Language)
+
+		flags := ((c.BitStreamMode & 0x07) << 5) | ((c.NumChannels & 0x0F) << 1)
+		if c.FullServiceAudio {
+			flags |= 0x01
+		}
+		b = append(b, flags)
+	}
+
+	return b
+}
+
+func (d *SpliceRawDescriptor) SpliceDescriptorTag() uint8 { return d.Tag }
+
+func (d *SpliceRawDescriptor) decode(r *spliceReader) {
+	d.Identifier = r.u32()
+	d.Data = append([]byte(nil), r.bytes(r.remain())...)
+}
+
+func (d *SpliceRawDescriptor) encode(b []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, d.Identifier)
+	return append(b, d.Data...)
+}
@@ -0,0 +1,378 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/cesbo/go-mpegts/crc32"
+	"github.com/stretchr/testify/assert"
+)
+
+// time_signal with segmentation_descriptor. SCTE 35 sample 14.1
+var testSpliceTimeSignal = []byte{
+	0xFC, 0x30, 0x34, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0xFF, 0xFF, 0xF0, 0x05, 0x06, 0xFE, 0x72,
+	0xBD, 0x00, 0x50, 0x00, 0x1E, 0x02, 0x1C, 0x43,
+	0x55, 0x45, 0x49, 0x48, 0x00, 0x00, 0x8E, 0x7F,
+	0xCF, 0x00, 0x01, 0xA5, 0x99, 0xB0, 0x08, 0x08,
+	0x00, 0x00, 0x00, 0x00, 0x2C, 0xA0, 0xA1, 0x8A,
+	0x34, 0x02, 0x00, 0x9A, 0xC9, 0xD1, 0x7E,
+}
+
+func TestSpliceInfo_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	s := new(SpliceInfo)
+	if !assert.NoError(s.Decode(testSpliceTimeSignal)) {
+		return
+	}
+
+	assert.Equal(uint8(3), s.SAPType)
+	assert.False(s.Encrypted)
+	assert.Equal(Timestamp(0), s.PTSAdjustment)
+	assert.Equal(uint16(0x0FFF), s.Tier)
+
+	command, ok := s.Command.(*TimeSignal)
+	if !assert.True(ok) {
+		return
+	}
+	assert.Equal(Timestamp(0x072BD0050), command.SpliceTime)
+
+	if !assert.Len(s.Descriptors, 1) {
+		return
+	}
+
+	desc, ok := s.Descriptors[0].(*SegmentationDescriptor)
+	if !assert.True(ok) {
+		return
+	}
+	assert.Equal(uint32(0x4800008E), desc.EventID)
+	assert.False(desc.Cancel)
+	assert.True(desc.ProgramSegmentation)
+	assert.False(desc.DeliveryNotRestricted)
+	assert.True(desc.NoRegionalBlackout)
+	assert.True(desc.ArchiveAllowed)
+	assert.Equal(uint8(3), desc.DeviceRestrictions)
+	assert.True(desc.HasDuration)
+	assert.Equal(uint64(27630000), desc.Duration)
+	assert.Equal(uint8(0x08), desc.UPIDType)
+	assert.Equal([]byte{0x00, 0x00, 0x00, 0x00, 0x2C, 0xA0, 0xA1, 0x8A}, desc.UPID)
+	assert.Equal(uint8(0x34), desc.TypeID)
+	assert.Equal(uint8(2), desc.SegmentNum)
+	assert.Equal(uint8(0), desc.SegmentsExpected)
+}
+
+func TestSpliceInfo_Decode_Error(t *testing.T) {
+	assert := assert.New(t)
+
+	s := new(SpliceInfo)
+
+	assert.ErrorIs(s.Decode(testSpliceTimeSignal[:10]), ErrSpliceFormat)
+
+	b := append([]byte(nil), testSpliceTimeSignal...)
+	b[0] = 0x00
+	assert.ErrorIs(s.Decode(b), ErrSpliceFormat)
+
+	// descriptor_length out of range
+	b = append([]byte(nil), testSpliceTimeSignal...)
+	b[22] = 0x30
+	assert.ErrorIs(s.Decode(b), ErrSpliceFormat)
+}
+
+func TestSpliceInfo_Decode_CommandPadding(t *testing.T) {
+	assert := assert.New(t)
+
+	// time_signal with 2 extension bytes after splice_time
+	const command = 19
+	b := append([]byte(nil), testSpliceTimeSignal[:command]...)
+	b = append(b, 0xAA, 0xBB)
+	b = append(b, testSpliceTimeSignal[command:len(testSpliceTimeSignal)-crc32.Size]...)
+	b[2] += 2  // section_length
+	b[12] += 2 // splice_command_length
+	b = binary.BigEndian.AppendUint32(b, crc32.Checksum(0xFFFFFFFF, b))
+
+	s := new(SpliceInfo)
+	if !assert.NoError(s.Decode(b)) {
+		return
+	}
+
+	if command, ok := s.Command.(*TimeSignal); assert.True(ok) {
+		assert.Equal(Timestamp(0x072BD0050), command.SpliceTime)
+	}
+
+	if assert.Len(s.Descriptors, 1) {
+		_, ok := s.Descriptors[0].(*SegmentationDescriptor)
+		assert.True(ok)
+	}
+}
+
+func TestSpliceInfo_SpliceInsert(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSpliceInfo()
+	s.PTSAdjustment = 0x1FFFFFFFF
+	s.Command = &SpliceInsert{
+		EventID:       0x12345678,
+		OutOfNetwork:  true,
+		ProgramSplice: true,
+		SpliceTime:    0x100000000,
+		BreakDuration: &BreakDuration{
+			AutoReturn: true,
+			Duration:   30 * 90000,
+		},
+		UniqueProgramID: 100,
+		AvailNum:        1,
+		AvailsExpected:  2,
+	}
+	s.Descriptors = []SpliceDescriptor{
+		&SpliceAvailDescriptor{ProviderAvailID: 0x00000135},
+		&SpliceDTMFDescriptor{Preroll: 50, Chars: "123*"},
+		&SpliceTimeDescriptor{
+			TAISeconds:     0x123456789A,
+			TAINanoseconds: 500000000,
+			UTCOffset:      37,
+		},
+		&SpliceAudioDescriptor{
+			Components: []SpliceAudioComponent{
+				{
+					Tag:              1,
+					Language:         "eng",
+					BitStreamMode:    2,
+					NumChannels:      5,
+					FullServiceAudio: true,
+				},
+			},
+		},
+		&SpliceRawDescriptor{
+			Tag:        0x80,
+			Identifier: 0x54455354,
+			Data:       []byte{0x01, 0x02, 0x03},
+		},
+	}
+
+	b, err := s.Encode()
+	if !assert.NoError(err) {
+		return
+	}
+
+	decoded := new(SpliceInfo)
+	if assert.NoError(decoded.Decode(b)) {
+		assert.Equal(s, decoded)
+	}
+}
+
+func TestSpliceInfo_Commands(t *testing.T) {
+	assert := assert.New(t)
+
+	commands := []SpliceCommand{
+		&SpliceNull{},
+		&BandwidthReservation{},
+		&TimeSignal{SpliceTime: NonTimestamp},
+		&SpliceInsert{
+			EventID:    1,
+			Cancel:     true,
+			SpliceTime: NonTimestamp,
+		},
+		&SpliceInsert{
+			EventID:         2,
+			SpliceImmediate: true,
+			SpliceTime:      NonTimestamp,
+			Components: []SpliceInsertComponent{
+				{Tag: 1, SpliceTime: NonTimestamp},
+				{Tag: 2, SpliceTime: NonTimestamp},
+			},
+		},
+		&SpliceInsert{
+			EventID:    3,
+			SpliceTime: NonTimestamp,
+			Components: []SpliceInsertComponent{
+				{Tag: 1, SpliceTime: 1000},
+				{Tag: 2, SpliceTime: NonTimestamp},
+			},
+		},
+		&SpliceSchedule{
+			Events: []SpliceScheduleEvent{
+				{
+					EventID: 1,
+					Cancel:  true,
+				},
+				{
+					EventID:       2,
+					OutOfNetwork:  true,
+					ProgramSplice: true,
+					UTCSpliceTime: 0x50000000,
+					BreakDuration: &BreakDuration{Duration: 60 * 90000},
+				},
+				{
+					EventID: 3,
+					Components: []SpliceScheduleComponent{
+						{Tag: 1, UTCSpliceTime: 0x50000010},
+					},
+					UniqueProgramID: 7,
+				},
+			},
+		},
+		&PrivateCommand{
+			Identifier: 0x54455354,
+			Data:       []byte{0xAA, 0xBB},
+		},
+	}
+
+	for _, command := range commands {
+		s := NewSpliceInfo()
+		s.Command = command
+
+		b, err := s.Encode()
+		if !assert.NoError(err) {
+			continue
+		}
+
+		decoded := new(SpliceInfo)
+		if assert.NoError(decoded.Decode(b)) {
+			assert.Equal(s, decoded)
+		}
+	}
+}
+
+func TestSpliceInfo_Segmentation(t *testing.T) {
+	assert := assert.New(t)
+
+	descriptors := []SpliceDescriptor{
+		&SegmentationDescriptor{
+			EventID: 1,
+			Cancel:  true,
+		},
+		&SegmentationDescriptor{
+			EventID:               2,
+			DeliveryNotRestricted: true,
+			Components: []SegmentationComponent{
+				{Tag: 1, PTSOffset: 0},
+				{Tag: 2, PTSOffset: 0x1FFFFFFFF},
+			},
+			UPIDType:            0x0C,
+			UPID:                []byte("MPU"),
+			TypeID:              0x36,
+			SegmentNum:          1,
+			SegmentsExpected:    2,
+			SubSegmentNum:       3,
+			SubSegmentsExpected: 4,
+		},
+	}
+
+	s := NewSpliceInfo()
+	s.Command = &TimeSignal{SpliceTime: 90000}
+	s.Descriptors = descriptors
+
+	b, err := s.Encode()
+	if !assert.NoError(err) {
+		return
+	}
+
+	decoded := new(SpliceInfo)
+	if assert.NoError(decoded.Decode(b)) {
+		assert.Equal(s, decoded)
+	}
+}
+
+func TestSpliceInfo_Encrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSpliceInfo()
+	s.Command = nil
+	s.Encrypted = true
+	s.EncryptionAlgorithm = 1
+	s.CWIndex = 5
+	s.EncryptedCommandLength = 5
+	s.EncryptedData = []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	b, err := s.Encode()
+	if !assert.NoError(err) {
+		return
+	}
+
+	decoded := new(SpliceInfo)
+	if assert.NoError(decoded.Decode(b)) {
+		assert.Equal(s, decoded)
+	}
+}
+
+func TestSpliceInfo_Packetize(t *testing.T) {
+	assert := assert.New(t)
+
+	s := new(SpliceInfo)
+	if !assert.NoError(s.Decode(testSpliceTimeSignal)) {
+		return
+	}
+
+	p, err := s.Packetizer()
+	if !assert.NoError(err) {
+		return
+	}
+
+	psi := new(PSI)
+	sections := 0
+
+	ts := NewTS(500)
+	for ; p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(err) {
+				return
+			}
+
+			sections += 1
+			assert.Equal(uint8(0xFC), psi.TableID)
+
+			decoded := new(SpliceInfo)
+			if assert.NoError(decoded.Decode(psi.Payload())) {
+				assert.Equal(s, decoded)
+			}
+		})
+	}
+
+	assert.Equal(1, sections)
+}
+
+func TestSpliceInfo_PacketizeLarge(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	s := NewSpliceInfo()
+	s.Command = &PrivateCommand{Identifier: 0x43554549, Data: data}
+
+	p, err := s.Packetizer()
+	if !assert.NoError(err) {
+		return
+	}
+
+	psi := new(PSI)
+	sections := 0
+	packets := 0
+
+	ts := NewTS(500)
+	for ; p.Next(ts); ts.IncrementCC() {
+		packets += 1
+		if !assert.Less(packets, 10) {
+			return
+		}
+
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(err) {
+				return
+			}
+
+			sections += 1
+
+			decoded := new(SpliceInfo)
+			if assert.NoError(decoded.Decode(psi.Payload())) {
+				assert.Equal(s, decoded)
+			}
+		})
+	}
+
+	assert.Equal(2, packets)
+	assert.Equal(1, sections)
+}
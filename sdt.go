@@ -276,18 +276,40 @@ func (s *SdtItem) Descriptors() Descriptors {
 	return Descriptors(s.header[SdtItemSize:])
 }
 
-// Appends descriptors to the last added item
-func (s *SdtItem) AppendDescriptors(desc Descriptors) {
+// Appends descriptors to the service descriptors.
+// Returns ErrDescriptorSize if descriptors_loop_length exceeds the limit.
+func (s *SdtItem) AppendDescriptors(desc Descriptors) error {
 	if len(desc) == 0 {
-		return
+		return nil
 	}
 
 	ds := len(s.header) - SdtItemSize + len(desc)
 	if ds > 0x0FFF {
-		panic("failed to append sdt item descriptors: size limit")
+		return ErrDescriptorSize
 	}
 	s.header = append(s.header, desc...)
 
+	s.setDescriptorsLength(ds)
+	return nil
+}
+
+// SetDescriptors replaces service descriptors
+func (s *SdtItem) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > 0x0FFF {
+		return ErrDescriptorSize
+	}
+
+	s.header = append(s.header[:SdtItemSize:SdtItemSize], desc...)
+
+	s.setDescriptorsLength(len(desc))
+	return nil
+}
+
+func (s *SdtItem) setDescriptorsLength(ds int) {
 	b := uint16(s.header[3]&0xF0) << 8
 	b |= uint16(ds)
 	binary.BigEndian.PutUint16(s.header[3:], b)
@@ -1,6 +1,7 @@
 package mpegts
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -176,3 +177,53 @@ func TestSDT_Packetize(t *testing.T) {
 	}
 	assert.Equal(2, counter)
 }
+
+func TestSDT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	item := NewSdtItem()
+	item.SetServiceID(1)
+	item.SetRunningStatus(4)
+	item.SetScrambled(true)
+	assert.NoError(item.AppendDescriptors((&Desc_48{ServiceType: 0x01, ServiceProviderName: "Cesbo", ServiceName: "Old"}).Encode()))
+	assert.NoError(item.AppendDescriptors(Descriptors{0x5F, 0x04, 0x00, 0x00, 0x00, 0x28}))
+
+	name := (&Desc_48{ServiceType: 0x01, ServiceProviderName: "Cesbo", ServiceName: "New Name"}).Encode()
+	assert.NoError(item.SetDescriptors(item.Descriptors().Replace(name)))
+
+	desc := item.Descriptors()
+	assert.NoError(desc.Check())
+	assert.Equal(int(binary.BigEndian.Uint16(item.header[3:])&0x0FFF), len(desc))
+	assert.Equal(uint8(4), item.RunningStatus())
+	assert.True(item.IsScrambled())
+
+	d := new(Desc_48)
+	if assert.NoError(d.Decode(desc.Find(0x48))) {
+		assert.Equal("New Name", d.ServiceName)
+	}
+	assert.NotNil(desc.Find(0x5F))
+
+	large := make(Descriptors, 0x1000)
+	assert.ErrorIs(item.AppendDescriptors(large), ErrDescriptorSize)
+}
+
+func TestDesc_48_Text(t *testing.T) {
+	assert := assert.New(t)
+
+	// service_provider_name: ISO/IEC 8859-5, service_name: GB2312
+	desc := Descriptors{
+		0x48, 0x0C, 0x01,
+		0x04, 0x01, 0xC0, 0xC2, 0xC1,
+		0x05, 0x13, 0xCC, 0xEC, 0xB5, 0xD8,
+	}
+
+	d := new(Desc_48)
+	if assert.NoError(d.Decode(desc)) {
+		assert.Equal("РТС", d.ServiceProviderName)
+		assert.Equal("天地", d.ServiceName)
+	}
+
+	assert.Equal(desc, d.Encode())
+
+	assert.ErrorIs(d.Decode(Descriptors{0x48, 0x03, 0x01, 0x05, 0x00}), ErrDescriptorFormat)
+}
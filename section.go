@@ -0,0 +1,296 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+const (
+	// Header of the section with section_syntax_indicator is 1:
+	// table_id, section_length, table_id_extension, version,
+	// section_number, last_section_number
+	SectionLongHeaderSize = 8
+
+	// Maximum section_length for PSI tables and DVB SI tables
+	SectionLengthLimit = 1021
+
+	// Maximum section_length for private sections and EIT
+	PrivateSectionLengthLimit = 4093
+)
+
+// Section is a generic PSI section.
+// Section with section_syntax_indicator is 1 (long section) contains
+// table_id_extension, version, section numbers, and CRC_32 at the end.
+// Section with section_syntax_indicator is 0 (short section) contains
+// only table_id and section_length before the section data.
+type Section []byte
+
+var (
+	ErrSectionFormat = errors.New("section: invalid format")
+	ErrSectionSize   = errors.New("section: size limit")
+)
+
+// SectionMaximumLength returns maximum section_length for the table_id
+func SectionMaximumLength(tableID uint8) int {
+	switch {
+	case tableID <= 0x03:
+		// PAT, CAT, PMT, TSDT
+		return SectionLengthLimit
+	case tableID >= 0x4E && tableID <= 0x6F:
+		// EIT
+		return PrivateSectionLengthLimit
+	case tableID >= 0x40 && tableID <= 0x7F:
+		// DVB SI
+		return SectionLengthLimit
+	default:
+		// private sections and DSM-CC sections
+		return PrivateSectionLengthLimit
+	}
+}
+
+// NewSection returns a new short section with data
+func NewSection(tableID uint8, data []byte) (Section, error) {
+	size := PsiHeaderSize + len(data)
+	if (size - PsiHeaderSize) > SectionMaximumLength(tableID) {
+		return nil, ErrSectionSize
+	}
+
+	s := make(Section, size)
+	s[0] = tableID
+	s[1] = 0x70
+	copy(s[PsiHeaderSize:], data)
+	s.setLength()
+
+	return s, nil
+}
+
+// NewLongSection returns a new long section with data.
+// Section is current, version and section numbers are 0.
+// CRC_32 is calculated on Finalize or by the Packetizer.
+func NewLongSection(tableID uint8, tableIDExtension uint16, data []byte) (Section, error) {
+	size := SectionLongHeaderSize + len(data) + crc32.Size
+	if (size - PsiHeaderSize) > SectionMaximumLength(tableID) {
+		return nil, ErrSectionSize
+	}
+
+	s := make(Section, size)
+	s[0] = tableID
+	s[1] = 0x80 | 0x30
+	binary.BigEndian.PutUint16(s[3:], tableIDExtension)
+	s[5] = 0xC0 | 0x01
+	copy(s[SectionLongHeaderSize:], data)
+	s.setLength()
+
+	return s, nil
+}
+
+func (s Section) setLength() {
+	length := len(s) - PsiHeaderSize
+	s[1] = (s[1] & 0xF0) | byte(length>>8)&0x0F
+	s[2] = byte(length)
+}
+
+func (s Section) TableID() uint8 {
+	return s[0]
+}
+
+func (s Section) SetTableID(id uint8) {
+	s[0] = id
+}
+
+// IsLong returns section_syntax_indicator
+func (s Section) IsLong() bool {
+	return (s[1] & 0x80) != 0
+}
+
+// IsPrivate returns private_indicator
+func (s Section) IsPrivate() bool {
+	return (s[1] & 0x40) != 0
+}
+
+func (s Section) SetPrivate(flag bool) {
+	if flag {
+		s[1] |= 0x40
+	} else {
+		s[1] &^= 0x40
+	}
+}
+
+// Length returns section_length.
+// Number of bytes of the section following the section_length field.
+func (s Section) Length() int {
+	return int(binary.BigEndian.Uint16(s[1:]) & 0x0FFF)
+}
+
+// Returns table_id_extension. Defined only for long sections
+func (s Section) TableIDExtension() uint16 {
+	return binary.BigEndian.Uint16(s[3:])
+}
+
+func (s Section) SetTableIDExtension(value uint16) {
+	binary.BigEndian.PutUint16(s[3:], value)
+}
+
+func (s Section) Version() uint8 {
+	return (s[5] & 0x3E) >> 1
+}
+
+func (s Section) SetVersion(version uint8) {
+	s[5] &^= 0x3E
+	s[5] |= (version << 1) & 0x3E
+}
+
+// IsCurrent returns current_next_indicator.
+// If false section is not yet applicable and shall be the next to become valid.
+func (s Section) IsCurrent() bool {
+	return (s[5] & 0x01) != 0
+}
+
+func (s Section) SetCurrent(flag bool) {
+	if flag {
+		s[5] |= 0x01
+	} else {
+		s[5] &^= 0x01
+	}
+}
+
+func (s Section) SectionNumber() uint8 {
+	return s[6]
+}
+
+func (s Section) SetSectionNumber(number uint8) {
+	s[6] = number
+}
+
+func (s Section) LastSectionNumber() uint8 {
+	return s[7]
+}
+
+func (s Section) SetLastSectionNumber(number uint8) {
+	s[7] = number
+}
+
+// Data returns section data after the header.
+// CRC_32 of the long section is not included.
+func (s Section) Data() []byte {
+	if s.IsLong() {
+		return s[SectionLongHeaderSize : len(s)-crc32.Size]
+	}
+
+	return s[PsiHeaderSize:]
+}
+
+// CRC returns CRC_32 of the long section
+func (s Section) CRC() uint32 {
+	return binary.BigEndian.Uint32(s[len(s)-crc32.Size:])
+}
+
+// Check validates section size, section numbers, and CRC_32 of the long section
+func (s Section) Check() error {
+	if len(s) < PsiHeaderSize || s[0] == 0xFF {
+		return ErrSectionFormat
+	}
+
+	length := s.Length()
+	if len(s) != (PsiHeaderSize + length) {
+		return ErrSectionFormat
+	}
+
+	if length > SectionMaximumLength(s[0]) {
+		return ErrSectionSize
+	}
+
+	if !s.IsLong() {
+		return nil
+	}
+
+	if len(s) < (SectionLongHeaderSize + crc32.Size) {
+		return ErrSectionFormat
+	}
+
+	if s.SectionNumber() > s.LastSectionNumber() {
+		return ErrSectionFormat
+	}
+
+	skip := len(s) - crc32.Size
+	if crc32.Checksum(0xFFFFFFFF, s[:skip]) != s.CRC() {
+		return ErrCRC
+	}
+
+	return nil
+}
+
+// Finalize sets section_length and CRC_32 of the long section
+func (s Section) Finalize() {
+	s.setLength()
+
+	if s.IsLong() {
+		skip := len(s) - crc32.Size
+		crc := crc32.Checksum(0xFFFFFFFF, s[:skip])
+		binary.BigEndian.PutUint32(s[skip:], crc)
+	}
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from Section.
+// section_length and CRC_32 are calculated by the packetizer.
+func (s Section) Packetizer() *PsiPacketizer {
+	if s.IsLong() {
+		return newPsiPacketizer(longSection(s))
+	}
+
+	return newPsiPacketizer(shortSection(s))
+}
+
+type longSection []byte
+
+func (s longSection) sectionSize(i int) int {
+	if i != -1 {
+		return 0
+	}
+
+	return len(s)
+}
+
+func (s longSection) sectionHeader(i int) []byte {
+	return s[:SectionLongHeaderSize]
+}
+
+func (s longSection) sectionItem(i int) []byte {
+	switch i {
+	case -1:
+		return []byte{}
+	case 0:
+		return s[SectionLongHeaderSize : len(s)-crc32.Size]
+	default:
+		return nil
+	}
+}
+
+type shortSection []byte
+
+func (s shortSection) sectionSize(i int) int {
+	if i != -1 {
+		return 0
+	}
+
+	return len(s)
+}
+
+func (s shortSection) sectionHeader(i int) []byte {
+	return s[:PsiHeaderSize]
+}
+
+func (s shortSection) sectionItem(i int) []byte {
+	switch i {
+	case -1:
+		return []byte{}
+	case 0:
+		return s[PsiHeaderSize:]
+	default:
+		return nil
+	}
+}
+
+func (s shortSection) withoutChecksum() {}
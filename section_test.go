@@ -0,0 +1,164 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assembleSection(t *testing.T, s Section, pid PID) []Section {
+	var result []Section
+
+	psi := new(PSI)
+	ts := NewTS(pid)
+	for p := s.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			b := make(Section, len(psi.Payload()))
+			copy(b, psi.Payload())
+			result = append(result, b)
+		})
+	}
+
+	return result
+}
+
+func TestSection_Long(t *testing.T) {
+	assert := assert.New(t)
+
+	data := make([]byte, 4000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	s, err := NewLongSection(0x80, 0x1234, data)
+	if !assert.NoError(err) {
+		return
+	}
+
+	s.SetVersion(7)
+	s.SetSectionNumber(1)
+	s.SetLastSectionNumber(2)
+
+	sections := assembleSection(t, s, 100)
+	if !assert.Equal(1, len(sections)) {
+		return
+	}
+
+	decoded := sections[0]
+	assert.NoError(decoded.Check())
+	assert.True(decoded.IsLong())
+	assert.False(decoded.IsPrivate())
+	assert.True(decoded.IsCurrent())
+	assert.Equal(uint8(0x80), decoded.TableID())
+	assert.Equal(4000+9, decoded.Length())
+	assert.Equal(uint16(0x1234), decoded.TableIDExtension())
+	assert.Equal(uint8(7), decoded.Version())
+	assert.Equal(uint8(1), decoded.SectionNumber())
+	assert.Equal(uint8(2), decoded.LastSectionNumber())
+	assert.Equal(data, decoded.Data())
+
+	// Finalize makes the same section as the packetizer
+	s.Finalize()
+	assert.Equal(s, decoded)
+}
+
+func TestSection_Short(t *testing.T) {
+	assert := assert.New(t)
+
+	data := []byte{0x01, 0x02, 0x03, 0x04, 0x05}
+
+	s, err := NewSection(0xC0, data)
+	if !assert.NoError(err) {
+		return
+	}
+	s.SetPrivate(true)
+
+	sections := assembleSection(t, s, 100)
+	if !assert.Equal(1, len(sections)) {
+		return
+	}
+
+	decoded := sections[0]
+	assert.NoError(decoded.Check())
+	assert.False(decoded.IsLong())
+	assert.True(decoded.IsPrivate())
+	assert.Equal(uint8(0xC0), decoded.TableID())
+	assert.Equal(len(data), decoded.Length())
+	assert.Equal(data, decoded.Data())
+	assert.Equal(s, decoded)
+}
+
+func TestSection_DSMCC(t *testing.T) {
+	assert := assert.New(t)
+
+	// DSM-CC section with download data block
+	s, err := NewLongSection(0x3C, 0x0001, make([]byte, 2000))
+	if !assert.NoError(err) {
+		return
+	}
+
+	psi := new(PSI)
+	ts := NewTS(200)
+	calls := 0
+	for p := s.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			calls += 1
+			if !assert.NoError(err) {
+				return
+			}
+
+			assert.Equal(uint8(0x3C), psi.TableID)
+			assert.Equal(uint16(0x0001), psi.TableIDExtension)
+			assert.Equal(uint8(0), psi.Version)
+			assert.Equal(SectionLongHeaderSize+2000+4, len(psi.Payload()))
+		})
+	}
+	assert.Equal(1, calls)
+}
+
+func TestSection_Limits(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(SectionLengthLimit, SectionMaximumLength(0x02))
+	assert.Equal(SectionLengthLimit, SectionMaximumLength(0x42))
+	assert.Equal(PrivateSectionLengthLimit, SectionMaximumLength(0x50))
+	assert.Equal(PrivateSectionLengthLimit, SectionMaximumLength(0x3B))
+	assert.Equal(PrivateSectionLengthLimit, SectionMaximumLength(0x80))
+
+	_, err := NewLongSection(0x02, 1, make([]byte, 1013))
+	assert.ErrorIs(err, ErrSectionSize)
+
+	_, err = NewLongSection(0x80, 1, make([]byte, 4090))
+	assert.ErrorIs(err, ErrSectionSize)
+
+	_, err = NewSection(0x80, make([]byte, 4093))
+	assert.NoError(err)
+
+	_, err = NewSection(0x80, make([]byte, 4094))
+	assert.ErrorIs(err, ErrSectionSize)
+}
+
+func TestSection_Check(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := NewLongSection(0x90, 1, []byte{0x01, 0x02, 0x03})
+	if !assert.NoError(err) {
+		return
+	}
+	s.Finalize()
+	assert.NoError(s.Check())
+
+	s[8] = 0x00
+	assert.ErrorIs(s.Check(), ErrCRC)
+
+	s.SetSectionNumber(2)
+	s.Finalize()
+	assert.ErrorIs(s.Check(), ErrSectionFormat)
+
+	assert.ErrorIs(s[:10].Check(), ErrSectionFormat)
+	assert.ErrorIs(Section{0xFF, 0x00, 0x00}.Check(), ErrSectionFormat)
+}
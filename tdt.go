@@ -0,0 +1,122 @@
+package mpegts
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	TdtSize = 8
+)
+
+// TDT is Time and Date Table.
+// Short section without section_syntax_indicator and checksum.
+type TDT struct {
+	header []byte
+}
+
+var (
+	ErrTdtFormat = errors.New("tdt: invalid format")
+)
+
+var (
+	emptyTdt = []byte{
+		0x70,                         // table_id
+		0x70,                         // section_length 1
+		0x05,                         // section_length 2
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // UTC_time
+	}
+)
+
+func NewTdt() *TDT {
+	t := new(TDT)
+	t.header = make([]byte, len(emptyTdt))
+	copy(t.header, emptyTdt)
+
+	return t
+}
+
+func (t *TDT) ParseTdtSection(b []byte) error {
+	if len(b) < TdtSize || b[0] != 0x70 {
+		return ErrTdtFormat
+	}
+
+	t.header = make([]byte, TdtSize)
+	copy(t.header, b)
+
+	return nil
+}
+
+// Time returns UTC time
+func (t *TDT) Time() time.Time {
+	return decodeMJD(t.header[3:])
+}
+
+// SetTime sets UTC time. Time is truncated to seconds
+func (t *TDT) SetTime(value time.Time) {
+	encodeMJD(t.header[3:], value)
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from TDT
+func (t *TDT) Packetizer() *PsiPacketizer {
+	return newPsiPacketizer(t)
+}
+
+func (t *TDT) withoutChecksum() {}
+
+func (t *TDT) sectionSize(i int) int {
+	if i != -1 {
+		return 0
+	}
+
+	return TdtSize
+}
+
+func (t *TDT) sectionHeader(i int) []byte {
+	return t.header[:TdtSize]
+}
+
+func (t *TDT) sectionItem(i int) []byte {
+	if i == -1 {
+		return []byte{}
+	}
+
+	return nil
+}
+
+// TimeTable is a table with UTC time: TDT or TOT
+type TimeTable interface {
+	Time() time.Time
+	SetTime(value time.Time)
+	Packetizer() *PsiPacketizer
+}
+
+// TimePacketizer emits time table with the current time at a fixed interval.
+// EN 300 468 requires TDT and TOT at least once every 30 seconds.
+type TimePacketizer struct {
+	table    TimeTable
+	interval time.Duration
+	last     time.Time
+}
+
+// NewTimePacketizer returns a new TimePacketizer for TDT or TOT
+func NewTimePacketizer(table TimeTable, interval time.Duration) *TimePacketizer {
+	return &TimePacketizer{
+		table:    table,
+		interval: interval,
+	}
+}
+
+// Update sets the current time into the table and returns PsiPacketizer
+// if interval is elapsed since the last emission.
+// Returns nil if table should not be sent yet.
+func (p *TimePacketizer) Update(now time.Time) *PsiPacketizer {
+	if !p.last.IsZero() && now.Sub(p.last) < p.interval {
+		return nil
+	}
+
+	p.last = now
+	p.table.SetTime(now)
+
+	return p.table.Packetizer()
+}
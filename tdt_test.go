@@ -0,0 +1,156 @@
+package mpegts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTDT_Packetize(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(1993, 10, 13, 12, 45, 0, 0, time.UTC)
+
+	tdt := NewTdt()
+	tdt.SetTime(now)
+
+	expected := TS{
+		0x47, 0x40, 0x14, 0x10, 0x00,
+		0x70, 0x70, 0x05, 0xC0, 0x79, 0x12, 0x45, 0x00,
+		0xFF, 0xFF, 0xFF, 0xFF,
+	}
+
+	counter := 0
+	ts := NewTS(20)
+	for p := tdt.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		assert.Equal(expected, ts[:len(expected)])
+		counter += 1
+	}
+	assert.Equal(1, counter)
+
+	psi := new(PSI)
+	psi.Assemble(ts, func(err error) {
+		if !assert.NoError(err) {
+			return
+		}
+
+		assert.Equal(uint8(0x70), psi.TableID)
+
+		decoded := new(TDT)
+		assert.NoError(decoded.ParseTdtSection(psi.Payload()))
+		assert.Equal(now, decoded.Time())
+	})
+}
+
+func TestTOT_Packetize(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2024, 3, 31, 0, 30, 15, 0, time.UTC)
+	change := time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC)
+
+	offset := &Desc_58{
+		Items: []Desc_58_Item{
+			{
+				Country:      "DEU",
+				Offset:       time.Hour,
+				TimeOfChange: change,
+				NextOffset:   2 * time.Hour,
+			},
+			{
+				Country:      "BRA",
+				Region:       1,
+				Offset:       -3 * time.Hour,
+				TimeOfChange: change,
+				NextOffset:   -3 * time.Hour,
+			},
+		},
+	}
+
+	tot := NewTot()
+	tot.SetTime(now)
+	assert.NoError(tot.AppendDescriptors(offset.Encode()))
+
+	psi := new(PSI)
+	sections := 0
+
+	ts := NewTS(20)
+	for p := tot.Packetizer(); p.Next(ts); ts.IncrementCC() {
+		psi.Assemble(ts, func(err error) {
+			if !assert.NoError(err) {
+				return
+			}
+
+			sections += 1
+			assert.Equal(uint8(0x73), psi.TableID)
+
+			decoded := new(TOT)
+			if !assert.NoError(decoded.ParseTotSection(psi.Payload())) {
+				return
+			}
+
+			assert.Equal(now, decoded.Time())
+
+			desc := new(Desc_58)
+			assert.NoError(desc.Decode(decoded.Descriptors()))
+			assert.Equal(offset, desc)
+		})
+	}
+	assert.Equal(1, sections)
+
+	// checksum error
+	ts = NewTS(20)
+	tot.Packetizer().Next(ts)
+	ts[10] ^= 0xFF
+	psi.Assemble(ts, func(err error) {
+		assert.ErrorIs(err, ErrCRC)
+	})
+}
+
+func TestTOT_EditDescriptors(t *testing.T) {
+	assert := assert.New(t)
+
+	tot := NewTot()
+	offset := (&Desc_58{Items: []Desc_58_Item{{Country: "DEU", Offset: time.Hour}}}).Encode()
+	assert.NoError(tot.SetDescriptors(offset))
+	assert.Equal(offset, tot.Descriptors())
+
+	large := make(Descriptors, TotMaximumSize)
+	assert.ErrorIs(tot.AppendDescriptors(large), ErrDescriptorSize)
+	assert.ErrorIs(tot.SetDescriptors(large), ErrDescriptorSize)
+	assert.Equal(offset, tot.Descriptors())
+}
+
+func TestDesc_58_Polarity(t *testing.T) {
+	assert := assert.New(t)
+
+	// single polarity bit for both offsets
+	d := &Desc_58{Items: []Desc_58_Item{
+		{Country: "GBR", Offset: 0, NextOffset: -time.Hour},
+		{Country: "DEU", Offset: time.Hour, NextOffset: -time.Hour},
+	}}
+
+	decoded := new(Desc_58)
+	if assert.NoError(decoded.Decode(d.Encode())) && assert.Equal(2, len(decoded.Items)) {
+		assert.Equal(-time.Hour, decoded.Items[0].NextOffset)
+		assert.Equal(time.Hour, decoded.Items[1].NextOffset)
+	}
+}
+
+func TestTimePacketizer_Update(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tdt := NewTdt()
+	p := NewTimePacketizer(tdt, 10*time.Second)
+
+	assert.NotNil(p.Update(now))
+	assert.Equal(now, tdt.Time())
+
+	assert.Nil(p.Update(now.Add(5 * time.Second)))
+	assert.Equal(now, tdt.Time())
+
+	assert.NotNil(p.Update(now.Add(10 * time.Second)))
+	assert.Equal(now.Add(10*time.Second), tdt.Time())
+}
@@ -0,0 +1,876 @@
+package textcode
+
+// ARIB STD-B24 : Part 2, Chapter 7. 8-unit code
+
+// Graphic sets by the final byte of the designation sequence
+const (
+	aribKanji        = 0x42
+	aribAlnum        = 0x4A
+	aribHiragana     = 0x30
+	aribKatakana     = 0x31
+	aribPropAlnum    = 0x36
+	aribPropHiragana = 0x37
+	aribPropKatakana = 0x38
+	aribJISKatakana  = 0x49
+	aribJISKanji1    = 0x39
+	aribJISKanji2    = 0x3A
+	aribSymbols      = 0x3B
+
+	// DRCS sets are designated with the 0x20 intermediate byte
+	aribDRCS  = 0x100
+	aribDRCS0 = aribDRCS | 0x40
+	aribMacro = aribDRCS | 0x70
+)
+
+// Control codes
+const (
+	aribAPR   = 0x0D // active position return
+	aribLS1   = 0x0E // locking shift 1
+	aribLS0   = 0x0F // locking shift 0
+	aribPAPF  = 0x16 // parameterized active position forward
+	aribSS2   = 0x19 // single shift 2
+	aribESC   = 0x1B // escape
+	aribAPS   = 0x1C // active position set
+	aribSS3   = 0x1D // single shift 3
+	aribSSZ   = 0x88 // small size
+	aribMSZ   = 0x89 // middle size
+	aribNSZ   = 0x8A // normal size
+	aribSZX   = 0x8B // character size controls
+	aribCOL   = 0x90 // color controls
+	aribFLC   = 0x91 // flashing control
+	aribCDC   = 0x92 // conceal display controls
+	aribPOL   = 0x93 // pattern polarity controls
+	aribWMM   = 0x94 // writing mode modification
+	aribMACRO = 0x95 // macro command
+	aribHLC   = 0x97 // highlighting character block
+	aribRPC   = 0x98 // repeat character
+	aribCSI   = 0x9B // control sequence introducer
+	aribTIME  = 0x9D // time controls
+)
+
+// Default macro statements 0x60-0x6F
+var aribMacros = [16][]byte{
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x4A, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x31, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x20, 0x41, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x32, 0x1B, 0x29, 0x34, 0x1B, 0x2A, 0x35, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x32, 0x1B, 0x29, 0x33, 0x1B, 0x2A, 0x35, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x32, 0x1B, 0x29, 0x20, 0x41, 0x1B, 0x2A, 0x35, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x20, 0x41, 0x1B, 0x29, 0x20, 0x42, 0x1B, 0x2A, 0x20, 0x43, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x20, 0x44, 0x1B, 0x29, 0x20, 0x45, 0x1B, 0x2A, 0x20, 0x46, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x20, 0x47, 0x1B, 0x29, 0x20, 0x48, 0x1B, 0x2A, 0x20, 0x49, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x20, 0x4A, 0x1B, 0x29, 0x20, 0x4B, 0x1B, 0x2A, 0x20, 0x4C, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x20, 0x4D, 0x1B, 0x29, 0x20, 0x4E, 0x1B, 0x2A, 0x20, 0x4F, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x20, 0x42, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x20, 0x43, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x24, 0x42, 0x1B, 0x29, 0x20, 0x44, 0x1B, 0x2A, 0x30, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x31, 0x1B, 0x29, 0x30, 0x1B, 0x2A, 0x4A, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+	{0x1B, 0x28, 0x4A, 0x1B, 0x29, 0x32, 0x1B, 0x2A, 0x20, 0x41, 0x1B, 0x2B, 0x20, 0x70, 0x0F, 0x1B, 0x7D},
+}
+
+// Additional symbols of the Kanji set, rows 90-94.
+// Cells without a single character equivalent in Unicode,
+// such as parenthesized instrument names, are not mapped.
+var aribSymbolsMap = map[uint16]rune{
+	0x7A21: 0x26CC,  // crossing lanes
+	0x7A22: 0x26CD,  // disabled car
+	0x7A23: 0x2757,  // heavy exclamation mark symbol
+	0x7A24: 0x26CF,  // pick
+	0x7A25: 0x26D0,  // car sliding
+	0x7A26: 0x26D1,  // helmet with white cross
+	0x7A28: 0x26D2,  // circled crossing lanes
+	0x7A29: 0x26D5,  // alternate one-way left way traffic
+	0x7A2A: 0x26D3,  // chains
+	0x7A2B: 0x26D4,  // no entry
+	0x7A30: 0x1F17F, // negative squared latin capital letter p
+	0x7A31: 0x1F18A, // crossed negative squared latin capital letter p
+	0x7A34: 0x26D6,  // black two-way left way traffic
+	0x7A35: 0x26D7,  // white two-way left way traffic
+	0x7A36: 0x26D8,  // black left lane merge
+	0x7A37: 0x26D9,  // white left lane merge
+	0x7A38: 0x26DA,  // drive slow sign
+	0x7A39: 0x26DB,  // heavy white down-pointing triangle
+	0x7A3A: 0x26DC,  // left closed entry
+	0x7A3B: 0x26DD,  // squared saltire
+	0x7A3C: 0x26DE,  // falling diagonal in white circle in black square
+	0x7A3D: 0x26DF,  // black truck
+	0x7A3E: 0x26E0,  // restricted left entry-1
+	0x7A3F: 0x26E1,  // restricted left entry-2
+	0x7A40: 0x2B55,  // heavy large circle
+	0x7A41: 0x3248,  // circled number ten on black square
+	0x7A42: 0x3249,  // circled number twenty on black square
+	0x7A43: 0x324A,  // circled number thirty on black square
+	0x7A44: 0x324B,  // circled number forty on black square
+	0x7A45: 0x324C,  // circled number fifty on black square
+	0x7A46: 0x324D,  // circled number sixty on black square
+	0x7A47: 0x324E,  // circled number seventy on black square
+	0x7A48: 0x324F,  // circled number eighty on black square
+	0x7A4D: 0x2491,  // number ten full stop
+	0x7A4E: 0x2492,  // number eleven full stop
+	0x7A4F: 0x2493,  // number twelve full stop
+	0x7A50: 0x1F14A, // HV
+	0x7A51: 0x1F14C, // SD
+	0x7A52: 0x1F13F, // P
+	0x7A53: 0x1F146, // W
+	0x7A54: 0x1F14B, // MV
+	0x7A55: 0x1F210, // 手
+	0x7A56: 0x1F211, // 字
+	0x7A57: 0x1F212, // 双
+	0x7A58: 0x1F213, // デ
+	0x7A59: 0x1F142, // S
+	0x7A5A: 0x1F214, // 二
+	0x7A5B: 0x1F215, // 多
+	0x7A5C: 0x1F216, // 解
+	0x7A5D: 0x1F14D, // SS
+	0x7A5E: 0x1F131, // B
+	0x7A5F: 0x1F13D, // N
+	0x7A60: 0x2B1B,  // black large square
+	0x7A61: 0x2B24,  // black large circle
+	0x7A62: 0x1F217, // 天
+	0x7A63: 0x1F218, // 交
+	0x7A64: 0x1F219, // 映
+	0x7A65: 0x1F21A, // 無
+	0x7A66: 0x1F21B, // 料
+	0x7A67: 0x26BF,  // age restriction
+	0x7A68: 0x1F21C, // 前
+	0x7A69: 0x1F21D, // 後
+	0x7A6A: 0x1F21E, // 再
+	0x7A6B: 0x1F21F, // 新
+	0x7A6C: 0x1F220, // 初
+	0x7A6D: 0x1F221, // 終
+	0x7A6E: 0x1F222, // 生
+	0x7A6F: 0x1F223, // 販
+	0x7A70: 0x1F224, // 声
+	0x7A71: 0x1F225, // 吹
+	0x7A72: 0x1F14E, // PPV
+	0x7A73: 0x3299,  // 秘
+	0x7A74: 0x1F200, // ほか
+	0x7B21: 0x26E3,  // heavy circle with stroke and two dots above
+	0x7B22: 0x2B56,  // heavy oval with oval inside
+	0x7B23: 0x2B57,  // heavy circle with circle inside
+	0x7B24: 0x2B58,  // heavy circle
+	0x7B25: 0x2B59,  // heavy circled saltire
+	0x7B26: 0x2613,  // saltire
+	0x7B27: 0x328B,  // circled ideograph fire
+	0x7B28: 0x3012,  // postal mark
+	0x7B29: 0x26E8,  // black cross on shield
+	0x7B2A: 0x3246,  // circled ideograph school
+	0x7B2B: 0x3245,  // circled ideograph kindergarten
+	0x7B2C: 0x26E9,  // shinto shrine
+	0x7B2D: 0xFD6,   // left-facing svasti sign
+	0x7B2E: 0x26EA,  // church
+	0x7B2F: 0x26EB,  // castle
+	0x7B30: 0x26EC,  // historic site
+	0x7B31: 0x2668,  // hot springs
+	0x7B32: 0x26ED,  // gear without hub
+	0x7B33: 0x26EE,  // gear with handles
+	0x7B34: 0x26EF,  // map symbol for lighthouse
+	0x7B35: 0x2693,  // anchor
+	0x7B36: 0x2708,  // airplane
+	0x7B37: 0x26F0,  // mountain
+	0x7B38: 0x26F1,  // umbrella on ground
+	0x7B39: 0x26F2,  // fountain
+	0x7B3A: 0x26F3,  // flag in hole
+	0x7B3B: 0x26F4,  // ferry
+	0x7B3C: 0x26F5,  // sailboat
+	0x7B3D: 0x1F157, // negative circled latin capital letter h
+	0x7B3E: 0x24B9,  // circled latin capital letter d
+	0x7B3F: 0x24C8,  // circled latin capital letter s
+	0x7B40: 0x26F6,  // square four corners
+	0x7B41: 0x1F15F, // negative circled latin capital letter p
+	0x7B42: 0x1F18B, // negative squared ic
+	0x7B43: 0x1F18D, // negative squared sa
+	0x7B44: 0x1F18C, // negative squared pa
+	0x7B45: 0x1F179, // negative squared latin capital letter j
+	0x7B46: 0x26F7,  // skier
+	0x7B47: 0x26F8,  // ice skate
+	0x7B48: 0x26F9,  // person with ball
+	0x7B49: 0x26FA,  // tent
+	0x7B4A: 0x1F17B, // negative squared latin capital letter l
+	0x7B4B: 0x260E,  // black telephone
+	0x7B4C: 0x26FB,  // japanese bank symbol
+	0x7B4D: 0x26FC,  // headstone graveyard symbol
+	0x7B4E: 0x26FD,  // fuel pump
+	0x7B4F: 0x26FE,  // cup on black square
+	0x7B50: 0x1F17C, // negative squared latin capital letter m
+	0x7B51: 0x26FF,  // white flag with horizontal middle black stripe
+	0x7C21: 0x27A1,  // black rightwards arrow
+	0x7C22: 0x2B05,  // leftwards black arrow
+	0x7C23: 0x2B06,  // upwards black arrow
+	0x7C24: 0x2B07,  // downwards black arrow
+	0x7C25: 0x2B2F,  // white vertical ellipse
+	0x7C26: 0x2B2E,  // black vertical ellipse
+	0x7C27: 0x5E74,  // 年
+	0x7C28: 0x6708,  // 月
+	0x7C29: 0x65E5,  // 日
+	0x7C2A: 0x5186,  // 円
+	0x7C2B: 0x33A1,  // square m squared
+	0x7C2C: 0x33A5,  // square m cubed
+	0x7C2D: 0x339D,  // square cm
+	0x7C2E: 0x33A0,  // square cm squared
+	0x7C2F: 0x33A4,  // square cm cubed
+	0x7C30: 0x1F100, // digit zero full stop
+	0x7C31: 0x2488,  // digit one full stop
+	0x7C32: 0x2489,  // digit two full stop
+	0x7C33: 0x248A,  // digit three full stop
+	0x7C34: 0x248B,  // digit four full stop
+	0x7C35: 0x248C,  // digit five full stop
+	0x7C36: 0x248D,  // digit six full stop
+	0x7C37: 0x248E,  // digit seven full stop
+	0x7C38: 0x248F,  // digit eight full stop
+	0x7C39: 0x2490,  // digit nine full stop
+	0x7C3A: 0x6C0F,  // 氏
+	0x7C3B: 0x526F,  // 副
+	0x7C3C: 0x5143,  // 元
+	0x7C3D: 0x6545,  // 故
+	0x7C3E: 0x524D,  // 前
+	0x7C3F: 0x65B0,  // 新
+	0x7C40: 0x1F101, // digit zero comma
+	0x7C41: 0x1F102, // digit one comma
+	0x7C42: 0x1F103, // digit two comma
+	0x7C43: 0x1F104, // digit three comma
+	0x7C44: 0x1F105, // digit four comma
+	0x7C45: 0x1F106, // digit five comma
+	0x7C46: 0x1F107, // digit six comma
+	0x7C47: 0x1F108, // digit seven comma
+	0x7C48: 0x1F109, // digit eight comma
+	0x7C49: 0x1F10A, // digit nine comma
+	0x7C4A: 0x3233,  // parenthesized ideograph society
+	0x7C4B: 0x3236,  // parenthesized ideograph financial
+	0x7C4C: 0x3232,  // parenthesized ideograph have
+	0x7C4D: 0x3231,  // parenthesized ideograph stock
+	0x7C4E: 0x3239,  // parenthesized ideograph represent
+	0x7C4F: 0x3244,  // circled ideograph question
+	0x7C50: 0x25B6,  // black right-pointing triangle
+	0x7C51: 0x25C0,  // black left-pointing triangle
+	0x7C52: 0x3016,  // left white lenticular bracket
+	0x7C53: 0x3017,  // right white lenticular bracket
+	0x7C54: 0x27D0,  // white diamond with centred dot
+	0x7C55: 0xB2,    // superscript two
+	0x7C56: 0xB3,    // superscript three
+	0x7C57: 0x1F12D, // circled cd
+	0x7C76: 0x1F12C, // circled italic latin capital letter r
+	0x7C77: 0x1F12B, // circled italic latin capital letter c
+	0x7C78: 0x3247,  // circled ideograph koto
+	0x7C79: 0x1F190, // square dj
+	0x7C7A: 0x1F226, // 演
+	0x7C7B: 0x213B,  // facsimile sign
+	0x7D21: 0x322A,  // parenthesized ideograph moon
+	0x7D22: 0x322B,  // parenthesized ideograph fire
+	0x7D23: 0x322C,  // parenthesized ideograph water
+	0x7D24: 0x322D,  // parenthesized ideograph wood
+	0x7D25: 0x322E,  // parenthesized ideograph metal
+	0x7D26: 0x322F,  // parenthesized ideograph earth
+	0x7D27: 0x3230,  // parenthesized ideograph sun
+	0x7D28: 0x3237,  // parenthesized ideograph congratulation
+	0x7D29: 0x337E,  // square era name meizi
+	0x7D2A: 0x337D,  // square era name taisyou
+	0x7D2B: 0x337C,  // square era name syouwa
+	0x7D2C: 0x337B,  // square era name heisei
+	0x7D2D: 0x2116,  // numero sign
+	0x7D2E: 0x2121,  // telephone sign
+	0x7D2F: 0x3036,  // circled postal mark
+	0x7D30: 0x26BE,  // baseball
+	0x7D31: 0x1F240, // 〔本〕
+	0x7D32: 0x1F241, // 〔三〕
+	0x7D33: 0x1F242, // 〔二〕
+	0x7D34: 0x1F243, // 〔安〕
+	0x7D35: 0x1F244, // 〔点〕
+	0x7D36: 0x1F245, // 〔打〕
+	0x7D37: 0x1F246, // 〔盗〕
+	0x7D38: 0x1F247, // 〔勝〕
+	0x7D39: 0x1F248, // 〔敗〕
+	0x7D3A: 0x1F12A, // tortoise shell bracketed latin capital letter s
+	0x7D3B: 0x1F227, // 投
+	0x7D3C: 0x1F228, // 捕
+	0x7D3D: 0x1F229, // 一
+	0x7D3E: 0x1F214, // 二
+	0x7D3F: 0x1F22A, // 三
+	0x7D40: 0x1F22B, // 遊
+	0x7D41: 0x1F22C, // 左
+	0x7D42: 0x1F22D, // 中
+	0x7D43: 0x1F22E, // 右
+	0x7D44: 0x1F22F, // 指
+	0x7D45: 0x1F230, // 走
+	0x7D46: 0x1F231, // 打
+	0x7D47: 0x3351,  // square rittoru
+	0x7D48: 0x338F,  // square kg
+	0x7D49: 0x3390,  // square hz
+	0x7D4A: 0x33CA,  // square ha
+	0x7D4B: 0x339E,  // square km
+	0x7D4C: 0x33A2,  // square km squared
+	0x7D4D: 0x3371,  // square hpa
+	0x7D50: 0xBD,    // vulgar fraction one half
+	0x7D51: 0x2189,  // vulgar fraction zero thirds
+	0x7D52: 0x2153,  // vulgar fraction one third
+	0x7D53: 0x2154,  // vulgar fraction two thirds
+	0x7D54: 0xBC,    // vulgar fraction one quarter
+	0x7D55: 0xBE,    // vulgar fraction three quarters
+	0x7D56: 0x2155,  // vulgar fraction one fifth
+	0x7D57: 0x2156,  // vulgar fraction two fifths
+	0x7D58: 0x2157,  // vulgar fraction three fifths
+	0x7D59: 0x2158,  // vulgar fraction four fifths
+	0x7D5A: 0x2159,  // vulgar fraction one sixth
+	0x7D5B: 0x215A,  // vulgar fraction five sixths
+	0x7D5C: 0x2150,  // vulgar fraction one seventh
+	0x7D5D: 0x215B,  // vulgar fraction one eighth
+	0x7D5E: 0x2151,  // vulgar fraction one ninth
+	0x7D5F: 0x2152,  // vulgar fraction one tenth
+	0x7D60: 0x2600,  // black sun with rays
+	0x7D61: 0x2601,  // cloud
+	0x7D62: 0x2602,  // umbrella
+	0x7D63: 0x26C4,  // snowman without snow
+	0x7D64: 0x2616,  // white shogi piece
+	0x7D65: 0x2617,  // black shogi piece
+	0x7D66: 0x26C9,  // turned white shogi piece
+	0x7D67: 0x26CA,  // turned black shogi piece
+	0x7D68: 0x2666,  // black diamond suit
+	0x7D69: 0x2665,  // black heart suit
+	0x7D6A: 0x2663,  // black club suit
+	0x7D6B: 0x2660,  // black spade suit
+	0x7D6C: 0x26CB,  // white diamond in square
+	0x7D6D: 0x29BF,  // circled bullet
+	0x7D6E: 0x203C,  // double exclamation mark
+	0x7D6F: 0x2049,  // exclamation question mark
+	0x7D70: 0x26C5,  // sun behind cloud
+	0x7D71: 0x2614,  // umbrella with rain drops
+	0x7D72: 0x26C6,  // rain
+	0x7D73: 0x2603,  // snowman
+	0x7D74: 0x26C7,  // black snowman
+	0x7D75: 0x26A1,  // high voltage sign
+	0x7D76: 0x26C8,  // thunder cloud and rain
+	0x7D78: 0x269E,  // three lines converging right
+	0x7D79: 0x269F,  // three lines converging left
+	0x7D7A: 0x266C,  // beamed sixteenth notes
+	0x7D7B: 0x260E,  // black telephone
+	0x7E21: 0x2160,  // roman numeral one
+	0x7E22: 0x2161,  // roman numeral two
+	0x7E23: 0x2162,  // roman numeral three
+	0x7E24: 0x2163,  // roman numeral four
+	0x7E25: 0x2164,  // roman numeral five
+	0x7E26: 0x2165,  // roman numeral six
+	0x7E27: 0x2166,  // roman numeral seven
+	0x7E28: 0x2167,  // roman numeral eight
+	0x7E29: 0x2168,  // roman numeral nine
+	0x7E2A: 0x2169,  // roman numeral ten
+	0x7E2B: 0x216A,  // roman numeral eleven
+	0x7E2C: 0x216B,  // roman numeral twelve
+	0x7E2D: 0x2470,  // circled number seventeen
+	0x7E2E: 0x2471,  // circled number eighteen
+	0x7E2F: 0x2472,  // circled number nineteen
+	0x7E30: 0x2473,  // circled number twenty
+	0x7E31: 0x2474,  // parenthesized digit one
+	0x7E32: 0x2475,  // parenthesized digit two
+	0x7E33: 0x2476,  // parenthesized digit three
+	0x7E34: 0x2477,  // parenthesized digit four
+	0x7E35: 0x2478,  // parenthesized digit five
+	0x7E36: 0x2479,  // parenthesized digit six
+	0x7E37: 0x247A,  // parenthesized digit seven
+	0x7E38: 0x247B,  // parenthesized digit eight
+	0x7E39: 0x247C,  // parenthesized digit nine
+	0x7E3A: 0x247D,  // parenthesized number ten
+	0x7E3B: 0x247E,  // parenthesized number eleven
+	0x7E3C: 0x247F,  // parenthesized number twelve
+	0x7E3D: 0x2480,  // parenthesized number thirteen
+	0x7E3E: 0x2481,  // parenthesized number fourteen
+	0x7E3F: 0x2482,  // parenthesized number fifteen
+	0x7E40: 0x2483,  // parenthesized number sixteen
+	0x7E41: 0x2484,  // parenthesized number seventeen
+	0x7E42: 0x2485,  // parenthesized number eighteen
+	0x7E43: 0x2486,  // parenthesized number nineteen
+	0x7E44: 0x2487,  // parenthesized number twenty
+	0x7E45: 0x1F110, // parenthesized latin capital letter a
+	0x7E46: 0x1F111, // parenthesized latin capital letter b
+	0x7E47: 0x1F112, // parenthesized latin capital letter c
+	0x7E48: 0x1F113, // parenthesized latin capital letter d
+	0x7E49: 0x1F114, // parenthesized latin capital letter e
+	0x7E4A: 0x1F115, // parenthesized latin capital letter f
+	0x7E4B: 0x1F116, // parenthesized latin capital letter g
+	0x7E4C: 0x1F117, // parenthesized latin capital letter h
+	0x7E4D: 0x1F118, // parenthesized latin capital letter i
+	0x7E4E: 0x1F119, // parenthesized latin capital letter j
+	0x7E4F: 0x1F11A, // parenthesized latin capital letter k
+	0x7E50: 0x1F11B, // parenthesized latin capital letter l
+	0x7E51: 0x1F11C, // parenthesized latin capital letter m
+	0x7E52: 0x1F11D, // parenthesized latin capital letter n
+	0x7E53: 0x1F11E, // parenthesized latin capital letter o
+	0x7E54: 0x1F11F, // parenthesized latin capital letter p
+	0x7E55: 0x1F120, // parenthesized latin capital letter q
+	0x7E56: 0x1F121, // parenthesized latin capital letter r
+	0x7E57: 0x1F122, // parenthesized latin capital letter s
+	0x7E58: 0x1F123, // parenthesized latin capital letter t
+	0x7E59: 0x1F124, // parenthesized latin capital letter u
+	0x7E5A: 0x1F125, // parenthesized latin capital letter v
+	0x7E5B: 0x1F126, // parenthesized latin capital letter w
+	0x7E5C: 0x1F127, // parenthesized latin capital letter x
+	0x7E5D: 0x1F128, // parenthesized latin capital letter y
+	0x7E5E: 0x1F129, // parenthesized latin capital letter z
+	0x7E5F: 0x3251,  // circled number twenty one
+	0x7E60: 0x3252,  // circled number twenty two
+	0x7E61: 0x3253,  // circled number twenty three
+	0x7E62: 0x3254,  // circled number twenty four
+	0x7E63: 0x3255,  // circled number twenty five
+	0x7E64: 0x3256,  // circled number twenty six
+	0x7E65: 0x3257,  // circled number twenty seven
+	0x7E66: 0x3258,  // circled number twenty eight
+	0x7E67: 0x3259,  // circled number twenty nine
+	0x7E68: 0x325A,  // circled number thirty
+	0x7E69: 0x325B,  // circled number thirty one
+	0x7E6A: 0x325C,  // circled number thirty two
+	0x7E6B: 0x325D,  // circled number thirty three
+	0x7E6C: 0x325E,  // circled number thirty four
+	0x7E6D: 0x325F,  // circled number thirty five
+	0x7E6E: 0x32B1,  // circled number thirty six
+	0x7E6F: 0x32B2,  // circled number thirty seven
+	0x7E70: 0x32B3,  // circled number thirty eight
+	0x7E71: 0x32B4,  // circled number thirty nine
+	0x7E72: 0x32B5,  // circled number forty
+	0x7E73: 0x32B6,  // circled number forty one
+	0x7E74: 0x32B7,  // circled number forty two
+	0x7E75: 0x32B8,  // circled number forty three
+	0x7E76: 0x32B9,  // circled number forty four
+	0x7E77: 0x32BA,  // circled number forty five
+	0x7E78: 0x32BB,  // circled number forty six
+	0x7E79: 0x32BC,  // circled number forty seven
+	0x7E7A: 0x32BD,  // circled number forty eight
+	0x7E7B: 0x32BE,  // circled number forty nine
+	0x7E7C: 0x32BF,  // circled number fifty
+}
+
+var aribSymbolsEncodeMap = map[rune]uint16{}
+
+// Symbols 0x77-0x7E of the Hiragana and Katakana sets
+var (
+	aribHiraganaSymbols = []rune("ゝゞー。「」、・")
+	aribKatakanaSymbols = []rune("ヽヾー。「」、・")
+)
+
+func init() {
+	// symbols repeated in several cells are encoded with the first one
+	for code, r := range aribSymbolsMap {
+		if prev, ok := aribSymbolsEncodeMap[r]; !ok || code < prev {
+			aribSymbolsEncodeMap[r] = code
+		}
+	}
+}
+
+type aribDecoder struct {
+	g      [4]uint16 // designated graphic sets G0-G3
+	gl     int       // set invoked to GL
+	gr     int       // set invoked to GR
+	ss     int       // single shift for the next character
+	half   bool      // middle or small character size
+	repeat int       // repeat next character
+	result []rune
+}
+
+// newAribDecoder returns decoder with initial state for the SI text
+func newAribDecoder() *aribDecoder {
+	return &aribDecoder{
+		g:  [4]uint16{aribKanji, aribAlnum, aribHiragana, aribKatakana},
+		gl: 0,
+		gr: 2,
+	}
+}
+
+func aribSetSize(set uint16) int {
+	switch set {
+	case aribKanji, aribJISKanji1, aribJISKanji2, aribSymbols, aribDRCS0:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func (d *aribDecoder) put(r rune) {
+	n := 1
+	if d.repeat > 1 {
+		n = d.repeat
+	}
+	d.repeat = 0
+
+	for ; n > 0; n-- {
+		d.result = append(d.result, r)
+	}
+}
+
+func aribKanjiRune(c1, c2 byte) rune {
+	if c2 < 0x21 || c2 > 0x7E {
+		return '�'
+	}
+
+	if c1 >= 0x7A {
+		if r, ok := aribSymbolsMap[uint16(c1)<<8|uint16(c2)]; ok {
+			return r
+		}
+		return '�'
+	}
+
+	mapSkip := (int(c1-0x21) * 94) + int(c2-0x21)
+
+	if r := decode_map_jisx0208[mapSkip]; r != 0x0000 {
+		return rune(r)
+	}
+
+	return '�'
+}
+
+func (d *aribDecoder) alnumRune(c byte) rune {
+	if d.half {
+		switch c {
+		case 0x5C:
+			return '¥'
+		case 0x7E:
+			return '‾'
+		default:
+			return rune(c)
+		}
+	}
+
+	switch c {
+	case 0x5C:
+		return '￥'
+	case 0x7E:
+		return '￣'
+	default:
+		return rune(c-0x21) + '！'
+	}
+}
+
+func (d *aribDecoder) graphicRune(set uint16, c1, c2 byte) rune {
+	switch set {
+	case aribKanji, aribJISKanji1, aribSymbols:
+		return aribKanjiRune(c1, c2)
+	case aribAlnum, aribPropAlnum:
+		return d.alnumRune(c1)
+	case aribHiragana, aribPropHiragana:
+		if c1 >= 0x77 {
+			return aribHiraganaSymbols[c1-0x77]
+		} else if c1 <= 0x73 {
+			return rune(c1-0x21) + 'ぁ'
+		}
+	case aribKatakana, aribPropKatakana:
+		if c1 >= 0x77 {
+			return aribKatakanaSymbols[c1-0x77]
+		} else if c1 <= 0x76 {
+			return rune(c1-0x21) + 'ァ'
+		}
+	case aribJISKatakana:
+		if c1 <= 0x5F {
+			return rune(c1-0x21) + '｡'
+		}
+	}
+
+	// mosaic, DRCS, and JIS X 0213 plane 2 are not supported
+	return '�'
+}
+
+// graphic puts character of the set. Returns position after the character
+func (d *aribDecoder) graphic(set uint16, src []byte, skip int) int {
+	c1 := src[skip-1] & 0x7F
+	c2 := byte(0)
+
+	if aribSetSize(set) == 2 {
+		if skip >= len(src) {
+			d.put('�')
+			return skip
+		}
+
+		c2 = src[skip] & 0x7F
+		skip += 1
+	}
+
+	if set == aribMacro {
+		if c1 >= 0x60 && c1 <= 0x6F {
+			d.decode(aribMacros[c1-0x60])
+		}
+		return skip
+	}
+
+	d.put(d.graphicRune(set, c1, c2))
+
+	return skip
+}
+
+// designate sets graphic set to Gn. Returns position after the final byte
+func (d *aribDecoder) designate(n int, src []byte, skip int) int {
+	if skip >= len(src) {
+		return skip
+	}
+
+	f := uint16(src[skip])
+	skip += 1
+
+	if f == 0x20 {
+		if skip >= len(src) {
+			return skip
+		}
+		f = aribDRCS | uint16(src[skip])
+		skip += 1
+	}
+
+	d.g[n] = f
+
+	return skip
+}
+
+// escape handles escape sequence. Returns position after the sequence
+func (d *aribDecoder) escape(src []byte, skip int) int {
+	if skip >= len(src) {
+		return skip
+	}
+
+	c := src[skip]
+	skip += 1
+
+	switch c {
+	case 0x6E: // LS2
+		d.gl = 2
+	case 0x6F: // LS3
+		d.gl = 3
+	case 0x7E: // LS1R
+		d.gr = 1
+	case 0x7D: // LS2R
+		d.gr = 2
+	case 0x7C: // LS3R
+		d.gr = 3
+	case 0x28, 0x29, 0x2A, 0x2B: // 1-byte G set or DRCS
+		return d.designate(int(c-0x28), src, skip)
+	case 0x24: // 2-byte G set or DRCS
+		if skip < len(src) && src[skip] >= 0x28 && src[skip] <= 0x2B {
+			return d.designate(int(src[skip]-0x28), src, skip+1)
+		}
+		return d.designate(0, src, skip)
+	}
+
+	return skip
+}
+
+// aribSkipUntil returns position after the first byte in range
+func aribSkipUntil(src []byte, skip int, from, to byte) int {
+	for skip < len(src) {
+		c := src[skip]
+		skip += 1
+		if c >= from && c <= to {
+			break
+		}
+	}
+
+	return skip
+}
+
+// aribParam returns parameter of the control code and position after it
+func aribParam(src []byte, skip int) (byte, int) {
+	if skip >= len(src) {
+		return 0, skip
+	}
+
+	return src[skip], skip + 1
+}
+
+// control handles C0 and C1 control codes. Returns position after parameters
+func (d *aribDecoder) control(c byte, src []byte, skip int) int {
+	var p byte
+
+	switch c {
+	case aribAPR:
+		d.put('\n')
+	case aribLS0:
+		d.gl = 0
+	case aribLS1:
+		d.gl = 1
+	case aribSS2:
+		d.ss = 2
+	case aribSS3:
+		d.ss = 3
+	case aribESC:
+		return d.escape(src, skip)
+	case aribPAPF, aribSZX, aribFLC, aribPOL, aribWMM, aribHLC:
+		_, skip = aribParam(src, skip)
+	case aribAPS:
+		_, skip = aribParam(src, skip)
+		_, skip = aribParam(src, skip)
+	case aribSSZ, aribMSZ:
+		d.half = true
+	case aribNSZ:
+		d.half = false
+	case aribCOL, aribCDC:
+		if p, skip = aribParam(src, skip); p == 0x20 {
+			_, skip = aribParam(src, skip)
+		}
+	case aribRPC:
+		p, skip = aribParam(src, skip)
+		d.repeat = int(p) - 0x40
+	case aribMACRO:
+		// skip macro definition till the end of definition: MACRO 0x4F
+		if p, skip = aribParam(src, skip); p == 0x40 || p == 0x41 {
+			for skip < len(src) {
+				skip = aribSkipUntil(src, skip, aribMACRO, aribMACRO)
+				if p, skip = aribParam(src, skip); p == 0x4F {
+					break
+				}
+			}
+		}
+	case aribCSI:
+		skip = aribSkipUntil(src, skip, 0x40, 0x6F)
+	case aribTIME:
+		switch p, skip = aribParam(src, skip); p {
+		case 0x20, 0x28:
+			_, skip = aribParam(src, skip)
+		case 0x29:
+			skip = aribSkipUntil(src, skip, 0x40, 0x43)
+		}
+	}
+
+	return skip
+}
+
+func (d *aribDecoder) decode(src []byte) {
+	skip := 0
+	for skip < len(src) {
+		c := src[skip]
+		skip += 1
+
+		switch {
+		case c == 0x20:
+			if d.half {
+				d.put(' ')
+			} else {
+				d.put('　')
+			}
+		case c > 0x20 && c < 0x7F:
+			set := d.g[d.gl]
+			if d.ss != 0 {
+				set = d.g[d.ss]
+				d.ss = 0
+			}
+			skip = d.graphic(set, src, skip)
+		case c > 0xA0 && c < 0xFF:
+			skip = d.graphic(d.g[d.gr], src, skip)
+		case c < 0x20 || (c >= 0x80 && c <= 0x9F):
+			skip = d.control(c, src, skip)
+		}
+	}
+}
+
+// DecodeARIB converts ARIB STD-B24 8-unit code into UTF-8.
+// Alphanumeric characters in normal size are converted to full-width forms.
+// DRCS and mosaic characters replaced with '�'.
+func DecodeARIB(src []byte) string {
+	d := newAribDecoder()
+	d.decode(src)
+	return string(d.result)
+}
+
+// aribAlnumCode returns code of the character in the Alphanumeric set or 0
+func aribAlnumCode(r rune) byte {
+	switch {
+	case r == '¥':
+		return 0x5C
+	case r == '‾':
+		return 0x7E
+	case r == '\\' || r == '~':
+		return 0
+	case r >= 0x20 && r < 0x7F:
+		return byte(r)
+	default:
+		return 0
+	}
+}
+
+// aribHiraganaCode returns code of the character in the Hiragana set or 0
+func aribHiraganaCode(r rune) byte {
+	if r >= 'ぁ' && r <= 'ん' {
+		return byte(r-'ぁ') + 0x21
+	}
+
+	for i, s := range aribHiraganaSymbols {
+		if r == s {
+			return byte(i) + 0x77
+		}
+	}
+
+	return 0
+}
+
+// aribKanjiCode returns code of the character in the Kanji set or 0
+// Characters of the JIS X 0208 are preferred to the additional symbols.
+func aribKanjiCode(r rune) uint16 {
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
+		pos := (hi_map_jisx0208[hi] * 0x100) + lo
+
+		if code := encode_map_jisx0208[pos]; code != 0x0000 {
+			return code
+		}
+	}
+
+	return aribSymbolsEncodeMap[r]
+}
+
+// EncodeARIB converts an UTF-8 string into ARIB STD-B24 8-unit code
+// with initial state for the SI text: Kanji set in GL, Hiragana set in GR.
+// ASCII characters encoded with the Alphanumeric set in middle size.
+func EncodeARIB(src string) []byte {
+	result, _ := tryEncodeARIB(src)
+	return result
+}
+
+// tryEncodeARIB converts an UTF-8 string into ARIB STD-B24 8-unit code.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeARIB(src string) ([]byte, bool) {
+	var result []byte
+	ok := true
+
+	gl := 0
+	half := false
+
+	for _, r := range src {
+		if r == '\n' {
+			result = append(result, aribAPR)
+			continue
+		}
+
+		c := aribAlnumCode(r)
+
+		if c == 0 {
+			if h := aribHiraganaCode(r); h != 0 {
+				if half {
+					result = append(result, aribNSZ)
+					half = false
+				}
+				result = append(result, h|0x80)
+				continue
+			}
+
+			if code := aribKanjiCode(r); code != 0x0000 {
+				if half {
+					result = append(result, aribNSZ)
+					half = false
+				}
+				if gl != 0 {
+					result = append(result, aribLS0)
+					gl = 0
+				}
+				result = append(result, (byte(code >> 8)), (byte(code & 0xFF)))
+				continue
+			}
+
+			c = '?'
+			ok = false
+		}
+
+		if !half {
+			result = append(result, aribMSZ)
+			half = true
+		}
+		if c != 0x20 && gl != 1 {
+			result = append(result, aribLS1)
+			gl = 1
+		}
+		result = append(result, c)
+	}
+
+	return result, ok
+}
@@ -0,0 +1,121 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestARIB_Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		coded    []byte
+		expected string
+	}{
+		{"kanji", []byte{0x46, 0x7C, 0x4B, 0x5C}, "日本"},
+		{"hiragana", []byte{0xAC, 0xCE}, "がの"},
+		{
+			"katakana",
+			[]byte{0x1B, 0x7C, 0xCB, 0xE5, 0xF9, 0xB9},
+			"ニュース",
+		},
+		{
+			"alnum half-width",
+			[]byte{0x89, 0x0E, 0x4E, 0x48, 0x4B, 0x20, 0x31, 0x5C},
+			"NHK 1¥",
+		},
+		{
+			"alnum full-width",
+			[]byte{0x0E, 0x4E, 0x48, 0x4B, 0x20, 0x31},
+			"ＮＨＫ　１",
+		},
+		{
+			"single shift",
+			[]byte{0x46, 0x7C, 0x19, 0x4E, 0x1D, 0x46, 0x4B, 0x5C},
+			"日のテ本",
+		},
+		{
+			"designation",
+			[]byte{0x1B, 0x28, 0x49, 0x36, 0x1B, 0x24, 0x29, 0x42, 0x1B, 0x7E, 0xC6, 0xFC},
+			"ｶ日",
+		},
+		{
+			"symbols",
+			[]byte{0x7A, 0x6B, 0x7A, 0x50, 0x7A, 0x21, 0x7A, 0x2C},
+			"🈟🅊⛌�",
+		},
+		{
+			"symbols rows 92-94",
+			[]byte{0x7C, 0x21, 0x7C, 0x29, 0x7D, 0x31, 0x7D, 0x50, 0x7E, 0x21, 0x7E, 0x7C, 0x7C, 0x58},
+			"➡日🉀½Ⅰ㊿�",
+		},
+		{
+			"macro",
+			[]byte{0x1B, 0x2B, 0x20, 0x70, 0x1B, 0x28, 0x4A, 0x1D, 0x60, 0x46, 0x7C},
+			"日",
+		},
+		{
+			"controls",
+			[]byte{0x90, 0x20, 0x41, 0x83, 0x9B, 0x30, 0x3B, 0x31, 0x20, 0x53, 0x46, 0x7C, 0x0D, 0x98, 0x43, 0xCE},
+			"日\nののの",
+		},
+		{"truncated", []byte{0x46, 0x7C, 0x4B}, "日�"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DecodeARIB(test.coded))
+		})
+	}
+}
+
+func TestARIB_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []byte
+	}{
+		{"kanji", "日本", []byte{0x46, 0x7C, 0x4B, 0x5C}},
+		{"hiragana", "がの", []byte{0xAC, 0xCE}},
+		{"katakana", "ニュース", []byte{0x25, 0x4B, 0x25, 0x65, 0xF9, 0x25, 0x39}},
+		{
+			"alnum",
+			"日本のNHK 1",
+			[]byte{0x46, 0x7C, 0x4B, 0x5C, 0xCE, 0x89, 0x0E, 0x4E, 0x48, 0x4B, 0x20, 0x31},
+		},
+		{
+			"mixed",
+			"A日\nＢ",
+			[]byte{0x89, 0x0E, 0x41, 0x8A, 0x0F, 0x46, 0x7C, 0x0D, 0x23, 0x42},
+		},
+		{"symbols", "🈟ニュース", []byte{0x7A, 0x6B, 0x25, 0x4B, 0x25, 0x65, 0xF9, 0x25, 0x39}},
+		{"symbols rows 92-94", "➡½Ⅰ㊿", []byte{0x7C, 0x21, 0x7D, 0x50, 0x7E, 0x21, 0x7E, 0x7C}},
+		{"repeated symbol", "☎", []byte{0x7B, 0x4B}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := EncodeARIB(test.text)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.text, DecodeARIB(result))
+		})
+	}
+
+	t.Run("not mapped", func(t *testing.T) {
+		result := EncodeARIB("日😀")
+		assert.Equal(t, []byte{0x46, 0x7C, 0x89, 0x0E, '?'}, result)
+	})
+}
+
+func TestARIB_Symbols(t *testing.T) {
+	assert := assert.New(t)
+
+	for code, r := range aribSymbolsMap {
+		coded := []byte{byte(code >> 8), byte(code)}
+		assert.Equal(string(r), DecodeARIB(coded), "%04X", code)
+
+		// same character in the JIS X 0208 or in the other cell
+		result := EncodeARIB(string(r))
+		assert.Equal(string(r), DecodeARIB(result), "%04X", code)
+	}
+}
@@ -0,0 +1,88 @@
+package textcode
+
+// EncodeBig5 converts an UTF-8 string into Big5 (Traditional Chinese)
+func EncodeBig5(src string) []byte {
+	result, _ := tryEncodeBig5(src)
+	return result
+}
+
+// tryEncodeBig5 converts an UTF-8 string into Big5.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeBig5(src string) ([]byte, bool) {
+	return tryEncode(src, appendBig5)
+}
+
+// appendBig5 appends Big5 code of the character. Returns false if not mapped
+func appendBig5(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
+
+	code := uint16(0)
+
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
+		pos := (hi_map_big5[hi] * 0x100) + lo
+		code = encode_map_big5[pos]
+	}
+
+	if code == 0x0000 {
+		return dst, false
+	}
+
+	return append(dst, (byte(code >> 8)), (byte(code & 0xFF))), true
+}
+
+// DecodeBig5 converts Big5 (Traditional Chinese) into UTF-8
+func DecodeBig5(src []byte) string {
+	var result []rune
+
+	// trail bytes: 0x40-0x7E and 0xA1-0xFE
+	const loSize = (0x7F - 0x40) + (0xFF - 0xA1)
+
+	skip := 0
+	for skip < len(src) {
+		b := src[skip]
+		skip += 1
+
+		if b <= 0x7F {
+			result = append(result, rune(b))
+			continue
+		}
+
+		if skip >= len(src) {
+			result = append(result, '�')
+			break
+		}
+
+		hi := int(b)
+		lo := int(src[skip])
+		skip += 1
+
+		if (hi < 0xA1) || (hi > 0xF9) {
+			result = append(result, '�')
+			continue
+		}
+
+		switch {
+		case lo >= 0x40 && lo <= 0x7E:
+			lo -= 0x40
+		case lo >= 0xA1 && lo <= 0xFE:
+			lo -= 0xA1 - (0x7F - 0x40)
+		default:
+			result = append(result, '�')
+			continue
+		}
+
+		mapSkip := ((hi - 0xA1) * loSize) + lo
+
+		if r := decode_map_big5[mapSkip]; r != 0x0000 {
+			result = append(result, rune(r))
+		} else {
+			result = append(result, '�')
+		}
+	}
+
+	return string(result)
+}
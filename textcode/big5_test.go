@@ -0,0 +1,36 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBig5(t *testing.T) {
+	utf8 := "中華電視 台視"
+
+	coded := []byte{
+		0xA4, 0xA4, 0xB5, 0xD8, 0xB9, 0x71, 0xB5, 0xF8, 0x20, 0xA5, 0x78, 0xB5,
+		0xF8,
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		result := EncodeBig5(utf8)
+		assert.Equal(t, coded, result)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		result := DecodeBig5(coded)
+		assert.Equal(t, utf8, result)
+	})
+
+	t.Run("not mapped", func(t *testing.T) {
+		result := EncodeBig5("电視")
+		assert.Equal(t, []byte{'?', 0xB5, 0xF8}, result)
+	})
+
+	t.Run("out of bond", func(t *testing.T) {
+		result := DecodeBig5([]byte{0xA4, 0x80, 0xFE, 0x40})
+		assert.Equal(t, "��", result)
+	})
+}
@@ -0,0 +1,248 @@
+package textcode
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// EN 300 468 : Annex A. Coding of text characters
+
+type dvbCharset struct {
+	selector []byte
+	encode   func(string) ([]byte, bool)
+	decode   func([]byte) string
+
+	// control codes 0x80-0x9F are prefixed with 0xE0 in two-byte tables
+	// and coded as U+E080-U+E09F in UTF-8
+	control []byte
+	// next returns size of the character at the beginning of the text
+	next func([]byte) int
+
+	encoding *Encoding
+}
+
+func dvbNextSingle(b []byte) int {
+	return 1
+}
+
+func dvbNextDouble(b []byte) int {
+	if len(b) < 2 {
+		return len(b)
+	}
+	return 2
+}
+
+// dvbNextMultibyte for tables with single-byte ASCII and two-byte characters
+func dvbNextMultibyte(b []byte) int {
+	if b[0] <= 0x7F {
+		return 1
+	}
+	return dvbNextDouble(b)
+}
+
+func iso8859Charset(selector []byte, e *Encoding) *dvbCharset {
+	return &dvbCharset{
+		selector: selector,
+		encode: func(s string) ([]byte, bool) {
+			return tryEncode(s, e.encode)
+		},
+		decode:   e.decode,
+		next:     dvbNextSingle,
+		encoding: e,
+	}
+}
+
+var (
+	dvbISO6937 = &dvbCharset{
+		selector: nil,
+		encode:   tryEncodeISO6937,
+		decode:   DecodeISO6937,
+		next:     dvbNextSingle,
+		encoding: ISO6937,
+	}
+
+	// ISO/IEC 8859 by the part number with selector 0x10 0x00 nn
+	dvbISO8859 = []*dvbCharset{
+		0x01: iso8859Charset([]byte{0x10, 0x00, 0x01}, ISO8859_1),
+		0x02: iso8859Charset([]byte{0x10, 0x00, 0x02}, ISO8859_2),
+		0x03: iso8859Charset([]byte{0x10, 0x00, 0x03}, ISO8859_3),
+		0x04: iso8859Charset([]byte{0x10, 0x00, 0x04}, ISO8859_4),
+		0x05: iso8859Charset([]byte{0x01}, ISO8859_5),
+		0x06: iso8859Charset([]byte{0x02}, ISO8859_6),
+		0x07: iso8859Charset([]byte{0x03}, ISO8859_7),
+		0x08: iso8859Charset([]byte{0x04}, ISO8859_8),
+		0x09: iso8859Charset([]byte{0x05}, ISO8859_9),
+		0x0A: iso8859Charset([]byte{0x06}, ISO8859_10),
+		0x0B: iso8859Charset([]byte{0x07}, ISO8859_11),
+		0x0C: nil,
+		0x0D: iso8859Charset([]byte{0x09}, ISO8859_13),
+		0x0E: iso8859Charset([]byte{0x0A}, ISO8859_14),
+		0x0F: iso8859Charset([]byte{0x0B}, ISO8859_15),
+		0x10: iso8859Charset([]byte{0x10, 0x00, 0x10}, ISO8859_16),
+	}
+
+	dvbUCS2 = &dvbCharset{
+		selector: []byte{0x11},
+		encode:   tryEncodeUCS2,
+		decode:   DecodeUTF16BE,
+		control:  []byte{0xE0},
+		next:     dvbNextDouble,
+		encoding: UCS2,
+	}
+
+	dvbKSX1001 = &dvbCharset{
+		selector: []byte{0x12},
+		encode:   tryEncodeKSX1001,
+		decode:   DecodeKSX1001,
+		control:  []byte{0xE0},
+		next:     dvbNextMultibyte,
+		encoding: KSX1001,
+	}
+
+	dvbGB2312 = &dvbCharset{
+		selector: []byte{0x13},
+		encode:   tryEncodeGB2312,
+		decode:   DecodeGB2312,
+		control:  []byte{0xE0},
+		next:     dvbNextMultibyte,
+		encoding: GB2312,
+	}
+
+	dvbUTF8 = &dvbCharset{
+		selector: []byte{0x15},
+		encode: func(s string) ([]byte, bool) {
+			return []byte(s), utf8.ValidString(s)
+		},
+		decode: func(b []byte) string {
+			return string(b)
+		},
+		// 0xEE could not be a continuation byte, so search byte by byte
+		control:  []byte{0xEE, 0x82},
+		next:     dvbNextSingle,
+		encoding: UTF8,
+	}
+
+	// charsets to encode text in order of preference
+	dvbEncodeOrder []*dvbCharset
+)
+
+func init() {
+	dvbEncodeOrder = append(dvbEncodeOrder, dvbISO6937)
+
+	// single byte selectors first
+	for _, cs := range dvbISO8859[0x05:] {
+		if cs != nil && len(cs.selector) == 1 {
+			dvbEncodeOrder = append(dvbEncodeOrder, cs)
+		}
+	}
+	for _, cs := range dvbISO8859[0x01:0x05] {
+		dvbEncodeOrder = append(dvbEncodeOrder, cs)
+	}
+
+	dvbEncodeOrder = append(dvbEncodeOrder, dvbGB2312, dvbKSX1001, dvbUCS2, dvbUTF8)
+}
+
+// tryEncodeUCS2 converts an UTF-8 string into ISO/IEC 10646 BMP (UCS-2 big-endian).
+// Returns false if string contains characters outside of the BMP.
+func tryEncodeUCS2(src string) ([]byte, bool) {
+	result := make([]byte, 0, len(src)*2)
+	ok := true
+
+	for _, r := range src {
+		if r > 0xFFFF || utf16.IsSurrogate(r) {
+			r = '?'
+			ok = false
+		}
+		result = append(result, byte(r>>8), byte(r))
+	}
+
+	return result, ok
+}
+
+// dvbSelector returns charset and text without character table selector
+func dvbSelector(src []byte) (*dvbCharset, []byte) {
+	c := src[0]
+
+	switch {
+	case c >= 0x20:
+		return dvbISO6937, src
+	case c >= 0x01 && c <= 0x0B:
+		// ISO/IEC 8859-5 ... ISO/IEC 8859-15
+		return dvbISO8859[c+4], src[1:]
+	case c == 0x10:
+		if len(src) < 3 || src[1] != 0x00 || int(src[2]) >= len(dvbISO8859) {
+			return nil, nil
+		}
+		return dvbISO8859[src[2]], src[3:]
+	case c == 0x11:
+		// ISO/IEC 10646 Basic Multilingual Plane
+		return dvbUCS2, src[1:]
+	case c == 0x12:
+		// KS X 1001-2004 Korean
+		return dvbKSX1001, src[1:]
+	case c == 0x13:
+		// GB-2312-1980 Simplified Chinese
+		return dvbGB2312, src[1:]
+	case c == 0x14:
+		// Big5 subset of ISO/IEC 10646. Coded as BMP
+		return dvbUCS2, src[1:]
+	case c == 0x15:
+		// UTF-8 encoding of ISO/IEC 10646
+		return dvbUTF8, src[1:]
+	case c == 0x1F:
+		// compressed text described by encoding_type_id is not supported
+		return nil, nil
+	default:
+		// reserved for future use
+		return dvbISO6937, src[1:]
+	}
+}
+
+// DecodeDVB converts DVB text with character table selector into UTF-8.
+// CR/LF control code replaced with new line, other control codes removed.
+// Returns empty string for unsupported character tables.
+func DecodeDVB(src []byte) string {
+	return dvbTextDefault.Decode(src)
+}
+
+// dvbEncodeCharset selects the character table with the smallest encoded size.
+// Returns charset and encoded text without selector.
+func dvbEncodeCharset(src string) (*dvbCharset, []byte) {
+	var (
+		result *dvbCharset
+		data   []byte
+	)
+
+	for _, cs := range dvbEncodeOrder {
+		text, ok := cs.encode(src)
+		if !ok {
+			continue
+		}
+
+		// text without selector should not begin with a selector byte
+		if cs.selector == nil && len(text) != 0 && text[0] < 0x20 {
+			continue
+		}
+
+		if result != nil && len(result.selector)+len(data) <= len(cs.selector)+len(text) {
+			continue
+		}
+
+		result = cs
+		data = text
+	}
+
+	if result == nil {
+		// invalid UTF-8 string
+		return dvbISO6937, EncodeISO6937(src)
+	}
+
+	return result, data
+}
+
+// EncodeDVB converts an UTF-8 string into DVB text.
+// Selects the character table with the smallest encoded size.
+// Text without selector is encoded with ISO/IEC 6937.
+func EncodeDVB(src string) []byte {
+	return dvbTextDefault.Encode(src)
+}
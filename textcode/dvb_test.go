@@ -0,0 +1,63 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDVB_Decode(t *testing.T) {
+	tests := []struct {
+		name     string
+		coded    []byte
+		expected string
+	}{
+		{"empty", []byte{}, ""},
+		{"iso6937", []byte{'N', 'e', 'w', 's', 0x20, 0xC2, 0x65}, "News é"},
+		{"iso8859-5", []byte{0x01, 0xBF, 0xE0, 0xD8, 0xD2, 0xD5, 0xE2}, "Привет"},
+		{"iso8859-7", []byte{0x03, 0xE3, 0xE5, 0xE9, 0xDC}, "γειά"},
+		{"iso8859-9", []byte{0x05, 0xDD, 0x73, 0x74, 0x61, 0x6E, 0x62, 0x75, 0x6C}, "İstanbul"},
+		{"iso8859-2", []byte{0x10, 0x00, 0x02, 0xA3, 0xF3, 0x64, 0xBC}, "Łódź"},
+		{"iso8859 invalid", []byte{0x10, 0x01, 0x02, 0x41}, ""},
+		{"ucs2", []byte{0x11, 0x04, 0x1F, 0x04, 0x40, 0x00, 0x21}, "Пр!"},
+		{"ksx1001", []byte{0x12, 0xC7, 0xD1, 0xB1, 0xB9}, "한국"},
+		{"gb2312", []byte{0x13, 0xCC, 0xEC, 0xB5, 0xD8}, "天地"},
+		{"big5 subset", []byte{0x14, 0x81, 0xFA, 0x57, 0x30}, "臺地"},
+		{"utf8", []byte{0x15, 0xE5, 0xA4, 0xA9}, "天"},
+		{"compressed", []byte{0x1F, 0x01, 0x41}, ""},
+		{"reserved", []byte{0x0C, 0x41}, "A"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, DecodeDVB(test.coded))
+		})
+	}
+}
+
+func TestDVB_Encode(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []byte
+	}{
+		{"empty", "", []byte{}},
+		{"ascii", "News", []byte{'N', 'e', 'w', 's'}},
+		{"iso6937", "Café", []byte{'C', 'a', 'f', 0xC2, 0x65}},
+		{"iso8859-5", "Привет", []byte{0x01, 0xBF, 0xE0, 0xD8, 0xD2, 0xD5, 0xE2}},
+		{"iso8859-7", "γειά", []byte{0x03, 0xE3, 0xE5, 0xE9, 0xDC}},
+		{"iso8859-13", "Łódź", []byte{0x09, 0xD9, 0xF3, 0x64, 0xEA}},
+		{"gb2312", "天地", []byte{0x13, 0xCC, 0xEC, 0xB5, 0xD8}},
+		{"ksx1001", "한국 TV", []byte{0x12, 0xC7, 0xD1, 0xB1, 0xB9, 0x20, 0x54, 0x56}},
+		{"ucs2", "Пก", []byte{0x11, 0x04, 0x1F, 0x0E, 0x01}},
+		{"utf8", "TV 😀", []byte{0x15, 'T', 'V', ' ', 0xF0, 0x9F, 0x98, 0x80}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := EncodeDVB(test.text)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.text, DecodeDVB(result))
+		})
+	}
+}
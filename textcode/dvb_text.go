@@ -0,0 +1,180 @@
+package textcode
+
+import (
+	"bytes"
+	"strings"
+)
+
+// EN 300 468 : Annex A.1. Control codes
+
+const (
+	DVBEmphasisOn  byte = 0x86 // character emphasis on
+	DVBEmphasisOff byte = 0x87 // character emphasis off
+	DVBNewLine     byte = 0x8A // CR/LF
+)
+
+// DVBText defines handling of the control codes in DVB text.
+// On decoding control codes are replaced with the defined strings,
+// empty string removes control code. Reserved control codes are removed.
+// On encoding the defined strings are replaced with control codes.
+//
+// Emphasis markers could be used to get short names of services and events:
+//
+//	t := &DVBText{EmphasisOn: "<b>", EmphasisOff: "</b>"}
+//	t.Decode(src) // "<b>BBC</b> One"
+type DVBText struct {
+	EmphasisOn  string
+	EmphasisOff string
+	NewLine     string
+}
+
+var dvbTextDefault = &DVBText{NewLine: "\n"}
+
+func (t *DVBText) replacement(code byte) string {
+	switch code {
+	case DVBEmphasisOn:
+		return t.EmphasisOn
+	case DVBEmphasisOff:
+		return t.EmphasisOff
+	case DVBNewLine:
+		return t.NewLine
+	default:
+		return ""
+	}
+}
+
+// controlCode returns control code at the beginning of the text or 0
+func (cs *dvbCharset) controlCode(src []byte) byte {
+	n := len(cs.control)
+	if len(src) <= n || !bytes.HasPrefix(src, cs.control) {
+		return 0
+	}
+
+	if c := src[n]; c >= 0x80 && c <= 0x9F {
+		return c
+	}
+
+	return 0
+}
+
+// Decode converts DVB text with character table selector into UTF-8.
+// Returns empty string for unsupported character tables.
+func (t *DVBText) Decode(src []byte) string {
+	if len(src) == 0 {
+		return ""
+	}
+
+	cs, text := dvbSelector(src)
+	if cs == nil {
+		return ""
+	}
+
+	var result strings.Builder
+
+	skip := 0
+	pos := 0
+	for pos < len(text) {
+		c := cs.controlCode(text[pos:])
+		if c == 0 {
+			pos += cs.next(text[pos:])
+			continue
+		}
+
+		if skip != pos {
+			result.WriteString(cs.decode(text[skip:pos]))
+		}
+		result.WriteString(t.replacement(c))
+
+		pos += len(cs.control) + 1
+		skip = pos
+	}
+
+	if skip == 0 {
+		return cs.decode(text)
+	}
+
+	if skip != len(text) {
+		result.WriteString(cs.decode(text[skip:]))
+	}
+
+	return result.String()
+}
+
+// split returns text parts and control codes between them
+func (t *DVBText) split(src string) ([]string, []byte) {
+	var (
+		parts []string
+		codes []byte
+	)
+
+	markers := [...]struct {
+		value string
+		code  byte
+	}{
+		{t.EmphasisOn, DVBEmphasisOn},
+		{t.EmphasisOff, DVBEmphasisOff},
+		{t.NewLine, DVBNewLine},
+	}
+
+	for {
+		pos := -1
+		size := 0
+		code := byte(0)
+
+		for _, m := range markers {
+			if m.value == "" {
+				continue
+			}
+
+			i := strings.Index(src, m.value)
+			if i == -1 {
+				continue
+			}
+
+			if pos == -1 || i < pos || (i == pos && len(m.value) > size) {
+				pos = i
+				size = len(m.value)
+				code = m.code
+			}
+		}
+
+		if pos == -1 {
+			break
+		}
+
+		parts = append(parts, src[:pos])
+		codes = append(codes, code)
+		src = src[pos+size:]
+	}
+
+	return append(parts, src), codes
+}
+
+// Encode converts an UTF-8 string into DVB text.
+// Selects the character table with the smallest encoded size.
+// Text without selector is encoded with ISO/IEC 6937.
+func (t *DVBText) Encode(src string) []byte {
+	parts, codes := t.split(src)
+	if len(codes) == 0 {
+		cs, text := dvbEncodeCharset(src)
+
+		result := make([]byte, 0, len(cs.selector)+len(text))
+		result = append(result, cs.selector...)
+		return append(result, text...)
+	}
+
+	cs, _ := dvbEncodeCharset(strings.Join(parts, ""))
+
+	result := append([]byte{}, cs.selector...)
+	for i, part := range parts {
+		if i != 0 {
+			result = append(result, cs.control...)
+			result = append(result, codes[i-1])
+		}
+
+		text, _ := cs.encode(part)
+		result = append(result, text...)
+	}
+
+	return result
+}
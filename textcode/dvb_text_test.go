@@ -0,0 +1,112 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDVBText_Decode(t *testing.T) {
+	markup := &DVBText{EmphasisOn: "<b>", EmphasisOff: "</b>", NewLine: "\n"}
+
+	tests := []struct {
+		name     string
+		coded    []byte
+		plain    string
+		expected string
+	}{
+		{
+			"iso6937",
+			[]byte{0x86, 'B', 'B', 'C', 0x87, ' ', 'O', 'n', 'e', 0x8A, 'N', 0xC2, 0x65},
+			"BBC One\nNé",
+			"<b>BBC</b> One\nNé",
+		},
+		{
+			"iso8859-5",
+			[]byte{0x01, 0x86, 0xBF, 0xE0, 0x87, 0xD8},
+			"При",
+			"<b>Пр</b>и",
+		},
+		{
+			"reserved",
+			[]byte{0x80, 'A', 0x9F},
+			"A",
+			"A",
+		},
+		{
+			"ucs2",
+			[]byte{0x11, 0xE0, 0x86, 0x04, 0x1F, 0xE0, 0x87, 0xE0, 0x8A, 0x00, 0x41},
+			"П\nA",
+			"<b>П</b>\nA",
+		},
+		{
+			"gb2312",
+			[]byte{0x13, 0xE0, 0x86, 0xCC, 0xEC, 0xE0, 0x87, 0xB5, 0xD8},
+			"天地",
+			"<b>天</b>地",
+		},
+		{
+			"utf8",
+			[]byte{0x15, 0xEE, 0x82, 0x86, 0xE5, 0xA4, 0xA9, 0xEE, 0x82, 0x87, 'A'},
+			"天A",
+			"<b>天</b>A",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.plain, DecodeDVB(test.coded))
+			assert.Equal(t, test.expected, markup.Decode(test.coded))
+		})
+	}
+}
+
+func TestDVBText_Encode(t *testing.T) {
+	markup := &DVBText{EmphasisOn: "<b>", EmphasisOff: "</b>", NewLine: "\n"}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected []byte
+	}{
+		{
+			"iso6937",
+			"<b>BBC</b> One\nNews",
+			[]byte{0x86, 'B', 'B', 'C', 0x87, ' ', 'O', 'n', 'e', 0x8A, 'N', 'e', 'w', 's'},
+		},
+		{
+			"iso8859-5",
+			"<b>Пр</b>и",
+			[]byte{0x01, 0x86, 0xBF, 0xE0, 0x87, 0xD8},
+		},
+		{
+			"gb2312",
+			"<b>天</b>地",
+			[]byte{0x13, 0xE0, 0x86, 0xCC, 0xEC, 0xE0, 0x87, 0xB5, 0xD8},
+		},
+		{
+			"ucs2",
+			"<b>П</b>ก",
+			[]byte{0x11, 0xE0, 0x86, 0x04, 0x1F, 0xE0, 0x87, 0x0E, 0x01},
+		},
+		{
+			"utf8",
+			"<b>TV</b> 😀",
+			[]byte{0x15, 0xEE, 0x82, 0x86, 'T', 'V', 0xEE, 0x82, 0x87, ' ', 0xF0, 0x9F, 0x98, 0x80},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := markup.Encode(test.text)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.text, markup.Decode(result))
+		})
+	}
+
+	t.Run("default", func(t *testing.T) {
+		result := EncodeDVB("<b>News</b>\nDaily")
+		assert.Equal(t, []byte("<b>News</b>\x8ADaily"), result)
+		assert.Equal(t, "<b>News</b>\nDaily", DecodeDVB(result))
+	})
+}
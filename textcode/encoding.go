@@ -0,0 +1,388 @@
+package textcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Encoding is a character set with streaming decoder and encoder.
+// Implements encoding.Encoding from golang.org/x/text.
+// Stateful ARIB STD-B24 code is not available as Encoding,
+// use DecodeARIB and EncodeARIB.
+type Encoding struct {
+	Name string
+
+	names  []string // aliases for Lookup
+	strict bool
+
+	// replacement is a code of the '?' for not mapped characters.
+	// Single byte '?' if nil
+	replacement []byte
+
+	// next returns size of the character at the beginning of the text.
+	// Returns 0 if character is incomplete
+	next func([]byte) int
+	// decode converts complete characters into UTF-8
+	decode func([]byte) string
+	// encode appends code of the character. Returns false if not mapped
+	encode func([]byte, rune) ([]byte, bool)
+}
+
+var ErrUnmappable = errors.New("textcode: unmappable character")
+
+// UnmappableError reports the first character that could not be encoded
+type UnmappableError struct {
+	Rune rune
+}
+
+func (e *UnmappableError) Error() string {
+	return fmt.Sprintf("textcode: unmappable character %q", e.Rune)
+}
+
+func (e *UnmappableError) Is(target error) bool {
+	return target == ErrUnmappable
+}
+
+// tryEncode converts an UTF-8 string with the character encoder fn.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncode(src string, fn func([]byte, rune) ([]byte, bool)) ([]byte, bool) {
+	var result []byte
+	ok := true
+
+	for _, r := range src {
+		var mapped bool
+		if result, mapped = fn(result, r); !mapped {
+			result = append(result, '?')
+			ok = false
+		}
+	}
+
+	return result, ok
+}
+
+func nextSingle(b []byte) int {
+	return 1
+}
+
+// nextMultibyte for tables with single-byte ASCII and two-byte characters
+func nextMultibyte(b []byte) int {
+	switch {
+	case b[0] <= 0x7F:
+		return 1
+	case len(b) < 2:
+		return 0
+	default:
+		return 2
+	}
+}
+
+func nextISO6937(b []byte) int {
+	switch {
+	case b[0] < 0xC1 || b[0] > 0xCF:
+		return 1
+	case len(b) < 2:
+		return 0
+	default:
+		return 2
+	}
+}
+
+func nextGB18030(b []byte) int {
+	switch {
+	case b[0] <= 0x80 || b[0] == 0xFF:
+		return 1
+	case len(b) < 2:
+		return 0
+	case b[1] < 0x30 || b[1] > 0x39:
+		return 2
+	case len(b) < 4:
+		return 0
+	default:
+		return 4
+	}
+}
+
+func nextUTF16BE(b []byte) int {
+	switch {
+	case len(b) < 2:
+		return 0
+	case b[0] < 0xD8 || b[0] > 0xDB:
+		// not a high surrogate
+		return 2
+	case len(b) < 4:
+		return 0
+	default:
+		return 4
+	}
+}
+
+func nextUCS2(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+
+	return 2
+}
+
+func nextUTF8(b []byte) int {
+	if !utf8.FullRune(b) {
+		return 0
+	}
+
+	_, size := utf8.DecodeRune(b)
+	return size
+}
+
+func iso8859Encoding(name string, hiMap []int, encodeMap []uint8, decodeMap []uint16) *Encoding {
+	return &Encoding{
+		Name:  name,
+		names: []string{name, strings.Replace(name, "ISO-", "ISO", 1)},
+		next:  nextSingle,
+		decode: func(b []byte) string {
+			return decodeISO8859(b, decodeMap)
+		},
+		encode: func(b []byte, r rune) ([]byte, bool) {
+			return appendISO8859(b, r, hiMap, encodeMap)
+		},
+	}
+}
+
+var (
+	ISO6937 = &Encoding{
+		Name:   "ISO-6937",
+		names:  []string{"ISO-6937", "ISO6937"},
+		next:   nextISO6937,
+		decode: DecodeISO6937,
+		encode: appendISO6937,
+	}
+
+	ISO8859_1  = iso8859Encoding("ISO-8859-1", hi_map_1, encode_map_1, decode_map_1)
+	ISO8859_2  = iso8859Encoding("ISO-8859-2", hi_map_2, encode_map_2, decode_map_2)
+	ISO8859_3  = iso8859Encoding("ISO-8859-3", hi_map_3, encode_map_3, decode_map_3)
+	ISO8859_4  = iso8859Encoding("ISO-8859-4", hi_map_4, encode_map_4, decode_map_4)
+	ISO8859_5  = iso8859Encoding("ISO-8859-5", hi_map_5, encode_map_5, decode_map_5)
+	ISO8859_6  = iso8859Encoding("ISO-8859-6", hi_map_6, encode_map_6, decode_map_6)
+	ISO8859_7  = iso8859Encoding("ISO-8859-7", hi_map_7, encode_map_7, decode_map_7)
+	ISO8859_8  = iso8859Encoding("ISO-8859-8", hi_map_8, encode_map_8, decode_map_8)
+	ISO8859_9  = iso8859Encoding("ISO-8859-9", hi_map_9, encode_map_9, decode_map_9)
+	ISO8859_10 = iso8859Encoding("ISO-8859-10", hi_map_10, encode_map_10, decode_map_10)
+	ISO8859_11 = iso8859Encoding("ISO-8859-11", hi_map_11, encode_map_11, decode_map_11)
+	ISO8859_13 = iso8859Encoding("ISO-8859-13", hi_map_13, encode_map_13, decode_map_13)
+	ISO8859_14 = iso8859Encoding("ISO-8859-14", hi_map_14, encode_map_14, decode_map_14)
+	ISO8859_15 = iso8859Encoding("ISO-8859-15", hi_map_15, encode_map_15, decode_map_15)
+	ISO8859_16 = iso8859Encoding("ISO-8859-16", hi_map_16, encode_map_16, decode_map_16)
+
+	GB2312 = &Encoding{
+		Name:   "GB2312",
+		names:  []string{"GB2312", "EUC-CN"},
+		next:   nextMultibyte,
+		decode: DecodeGB2312,
+		encode: appendGB2312,
+	}
+
+	GB18030 = &Encoding{
+		Name:   "GB18030",
+		names:  []string{"GB18030"},
+		next:   nextGB18030,
+		decode: DecodeGB18030,
+		encode: appendGB18030,
+	}
+
+	KSX1001 = &Encoding{
+		Name:   "KS X 1001",
+		names:  []string{"KS X 1001", "EUC-KR"},
+		next:   nextMultibyte,
+		decode: DecodeKSX1001,
+		encode: appendKSX1001,
+	}
+
+	Big5 = &Encoding{
+		Name:   "Big5",
+		names:  []string{"Big5"},
+		next:   nextMultibyte,
+		decode: DecodeBig5,
+		encode: appendBig5,
+	}
+
+	UTF16BE = &Encoding{
+		Name:   "UTF-16BE",
+		names:  []string{"UTF-16BE"},
+		next:   nextUTF16BE,
+		decode: DecodeUTF16BE,
+		encode: appendUTF16BE,
+	}
+
+	// UCS2 is ISO/IEC 10646 BMP big-endian.
+	// Characters outside of the BMP are not mapped.
+	UCS2 = &Encoding{
+		Name:        "UCS-2",
+		names:       []string{"UCS-2", "UCS-2BE"},
+		replacement: []byte{0x00, '?'},
+		next:        nextUCS2,
+		decode:      decodeUCS2,
+		encode:      appendUCS2,
+	}
+
+	UTF8 = &Encoding{
+		Name:  "UTF-8",
+		names: []string{"UTF-8"},
+		next:  nextUTF8,
+		decode: func(b []byte) string {
+			return strings.ToValidUTF8(string(b), "\uFFFD")
+		},
+		encode: func(b []byte, r rune) ([]byte, bool) {
+			return utf8.AppendRune(b, r), true
+		},
+	}
+
+	encodings = []*Encoding{
+		ISO6937,
+		ISO8859_1, ISO8859_2, ISO8859_3, ISO8859_4, ISO8859_5,
+		ISO8859_6, ISO8859_7, ISO8859_8, ISO8859_9, ISO8859_10,
+		ISO8859_11, ISO8859_13, ISO8859_14, ISO8859_15, ISO8859_16,
+		GB2312, GB18030, KSX1001, Big5, UTF16BE, UCS2, UTF8,
+	}
+)
+
+// encodingName returns name in lower case without separators
+func encodingName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ':
+			return -1
+		default:
+			return r
+		}
+	}, strings.ToLower(name))
+}
+
+// Lookup returns encoding by name. Name is case insensitive,
+// hyphens, underscores, and spaces are ignored.
+// Returns nil if encoding not found.
+func Lookup(name string) *Encoding {
+	name = encodingName(name)
+
+	for _, e := range encodings {
+		for _, n := range e.names {
+			if encodingName(n) == name {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+// LookupDVB returns encoding of the DVB text by character table selector
+// and text without selector. Control codes are not handled by the encoding.
+// Returns nil for unsupported character tables.
+func LookupDVB(src []byte) (*Encoding, []byte) {
+	if len(src) == 0 {
+		return ISO6937, src
+	}
+
+	cs, text := dvbSelector(src)
+	if cs == nil {
+		return nil, nil
+	}
+
+	return cs.encoding, text
+}
+
+func (e *Encoding) String() string {
+	return e.Name
+}
+
+// Strict returns a copy of the encoding with encoder
+// that fails with UnmappableError on not mapped characters
+// and with encoding.ErrInvalidUTF8 on invalid input.
+// By default not mapped characters replaced with '?'.
+func (e *Encoding) Strict() *Encoding {
+	s := *e
+	s.strict = true
+	return &s
+}
+
+// NewDecoder returns a decoder to convert text into UTF-8
+func (e *Encoding) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: &decoder{e: e}}
+}
+
+// NewEncoder returns an encoder to convert UTF-8 into text
+func (e *Encoding) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: &encoder{e: e}}
+}
+
+type decoder struct {
+	transform.NopResetter
+	e *Encoding
+}
+
+func (d *decoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		n := d.e.next(src[nSrc:])
+		if n == 0 {
+			if !atEOF {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			n = len(src) - nSrc
+		}
+
+		s := d.e.decode(src[nSrc : nSrc+n])
+		if nDst+len(s) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		nDst += copy(dst[nDst:], s)
+		nSrc += n
+	}
+
+	return nDst, nSrc, nil
+}
+
+type encoder struct {
+	transform.NopResetter
+	e   *Encoding
+	buf [8]byte
+}
+
+func (t *encoder) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for nSrc < len(src) {
+		r, size := utf8.DecodeRune(src[nSrc:])
+
+		if r == utf8.RuneError && size == 1 {
+			if !atEOF && !utf8.FullRune(src[nSrc:]) {
+				return nDst, nSrc, transform.ErrShortSrc
+			}
+			if t.e.strict {
+				return nDst, nSrc, encoding.ErrInvalidUTF8
+			}
+		}
+
+		code, ok := t.e.encode(t.buf[:0], r)
+		if !ok {
+			if t.e.strict {
+				return nDst, nSrc, &UnmappableError{Rune: r}
+			}
+			if t.e.replacement != nil {
+				code = append(code[:0], t.e.replacement...)
+			} else {
+				code = append(code[:0], '?')
+			}
+		}
+
+		if nDst+len(code) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+
+		nDst += copy(dst[nDst:], code)
+		nSrc += size
+	}
+
+	return nDst, nSrc, nil
+}
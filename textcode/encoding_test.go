@@ -0,0 +1,125 @@
+package textcode
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+var _ encoding.Encoding = ISO6937
+
+func TestEncoding_Decoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		e        *Encoding
+		coded    []byte
+		expected string
+	}{
+		{"iso6937", ISO6937, []byte{'C', 'a', 'f', 0xC2, 0x65}, "Café"},
+		{"iso8859-5", ISO8859_5, []byte{0xBF, 0xE0, 0xD8, 0xD2, 0xD5, 0xE2}, "Привет"},
+		{"gb2312", GB2312, []byte{0xCC, 0xEC, 0x20, 0xB5, 0xD8}, "天 地"},
+		{
+			"gb18030",
+			GB18030,
+			[]byte{0xD6, 0xD0, 0xA2, 0xE3, 0x81, 0x30, 0x81, 0x30, 0x94, 0x39, 0xFC, 0x36},
+			"中€\u0080😀",
+		},
+		{"utf16be", UTF16BE, []byte{0x04, 0x1F, 0xD8, 0x3D, 0xDE, 0x00}, "П😀"},
+		{"ucs2", UCS2, []byte{0x04, 0x1F, 0xD8, 0x3D, 0xDE, 0x00}, "П��"},
+		{"utf8", UTF8, []byte{0xE5, 0xA4, 0xA9, 0xFF}, "天�"},
+		{"incomplete", KSX1001, []byte{0xC7, 0xD1, 0xB1}, "한�"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := transform.NewReader(
+				iotest.OneByteReader(bytes.NewReader(test.coded)),
+				test.e.NewDecoder())
+			result, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestEncoding_Encoder(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		w := transform.NewWriter(buf, Big5.NewEncoder())
+		for _, b := range []byte("中華 電視") {
+			_, err := w.Write([]byte{b})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		assert.Equal(t, []byte{0xA4, 0xA4, 0xB5, 0xD8, 0x20, 0xB9, 0x71, 0xB5, 0xF8}, buf.Bytes())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		result, err := ISO8859_5.NewEncoder().String("Привет 😀")
+		assert.NoError(t, err)
+		assert.Equal(t, "\xBF\xE0\xD8\xD2\xD5\xE2 ?", result)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		_, err := ISO8859_5.Strict().NewEncoder().String("Привет 😀")
+		assert.ErrorIs(t, err, ErrUnmappable)
+		if e, ok := err.(*UnmappableError); assert.True(t, ok) {
+			assert.Equal(t, '😀', e.Rune)
+		}
+
+		_, err = UTF8.Strict().NewEncoder().Bytes([]byte{0x41, 0xFF})
+		assert.ErrorIs(t, err, encoding.ErrInvalidUTF8)
+
+		result, err := GB2312.Strict().NewEncoder().String("天地")
+		assert.NoError(t, err)
+		assert.Equal(t, "\xCC\xEC\xB5\xD8", result)
+
+		_, err = UCS2.Strict().NewEncoder().String("П😀")
+		assert.ErrorIs(t, err, ErrUnmappable)
+	})
+
+	t.Run("ucs2 replace", func(t *testing.T) {
+		result, err := UCS2.NewEncoder().String("П😀")
+		assert.NoError(t, err)
+		assert.Equal(t, "\x04\x1F\x00?", result)
+	})
+}
+
+func TestEncoding_Lookup(t *testing.T) {
+	assert.Equal(t, ISO8859_5, Lookup("iso_8859_5"))
+	assert.Equal(t, ISO8859_15, Lookup("ISO8859-15"))
+	assert.Equal(t, KSX1001, Lookup("EUC-KR"))
+	assert.Equal(t, KSX1001, Lookup("ksx1001"))
+	assert.Equal(t, UTF16BE, Lookup("utf-16be"))
+	assert.Equal(t, UCS2, Lookup("UCS-2"))
+	assert.Nil(t, Lookup("ISO-8859-12"))
+
+	e, text := LookupDVB([]byte{0x01, 0xBF})
+	assert.Equal(t, ISO8859_5, e)
+	assert.Equal(t, []byte{0xBF}, text)
+
+	e, text = LookupDVB([]byte{0x10, 0x00, 0x02, 0xA3})
+	assert.Equal(t, ISO8859_2, e)
+	assert.Equal(t, []byte{0xA3}, text)
+
+	e, _ = LookupDVB([]byte{0x12, 0xC7, 0xD1})
+	assert.Equal(t, KSX1001, e)
+
+	e, _ = LookupDVB([]byte{0x11, 0x04, 0x1F})
+	assert.Equal(t, UCS2, e)
+
+	e, _ = LookupDVB([]byte{0x14, 0x4E, 0x2D})
+	assert.Equal(t, UCS2, e)
+
+	e, text = LookupDVB([]byte{'A'})
+	assert.Equal(t, ISO6937, e)
+	assert.Equal(t, []byte{'A'}, text)
+
+	e, _ = LookupDVB([]byte{0x1F, 0x01})
+	assert.Nil(t, e)
+}
@@ -0,0 +1,163 @@
+package textcode
+
+import (
+	"sort"
+)
+
+const (
+	// Number of the four-byte codes in the BMP
+	gb18030BMPSize = 39420
+
+	// Pointer of the first four-byte code for supplementary planes
+	gb18030SupplementaryPointer = 189000
+)
+
+// gb18030RangeRune returns code point for the four-byte code pointer in the BMP
+func gb18030RangeRune(pointer int) rune {
+	i := sort.Search(len(ranges_gb18030), func(i int) bool {
+		return int(ranges_gb18030[i][0]) > pointer
+	}) - 1
+
+	return rune(int(ranges_gb18030[i][1]) + pointer - int(ranges_gb18030[i][0]))
+}
+
+// gb18030RangePointer returns four-byte code pointer for code point in the BMP.
+// Returns -1 if code point is not in the four-byte ranges
+func gb18030RangePointer(r rune) int {
+	i := sort.Search(len(ranges_gb18030), func(i int) bool {
+		return rune(ranges_gb18030[i][1]) > r
+	}) - 1
+
+	pointer := int(ranges_gb18030[i][0]) + int(r) - int(ranges_gb18030[i][1])
+
+	// range ends on the first pointer of the next range
+	end := gb18030BMPSize
+	if i+1 < len(ranges_gb18030) {
+		end = int(ranges_gb18030[i+1][0])
+	}
+
+	if pointer >= end {
+		return -1
+	}
+
+	return pointer
+}
+
+// EncodeGB18030 converts an UTF-8 string into GB18030 (Chinese)
+func EncodeGB18030(src string) []byte {
+	result, _ := tryEncode(src, appendGB18030)
+	return result
+}
+
+// appendGB18030 appends GB18030 code of the character.
+// Returns false for surrogates and code points outside of the Unicode range
+func appendGB18030(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
+
+	var pointer int
+
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
+		pos := (hi_map_gb18030[hi] * 0x100) + lo
+
+		if code := encode_map_gb18030[pos]; code != 0x0000 {
+			return append(dst, (byte(code >> 8)), (byte(code & 0xFF))), true
+		}
+
+		if pointer = gb18030RangePointer(r); pointer == -1 {
+			return dst, false
+		}
+	} else if r <= 0x10FFFF {
+		pointer = gb18030SupplementaryPointer + int(r-0x10000)
+	} else {
+		return dst, false
+	}
+
+	return append(dst,
+		byte(0x81+pointer/12600),
+		byte(0x30+(pointer/1260)%10),
+		byte(0x81+(pointer/10)%126),
+		byte(0x30+pointer%10),
+	), true
+}
+
+// DecodeGB18030 converts GB18030 (Chinese) into UTF-8
+func DecodeGB18030(src []byte) string {
+	var result []rune
+
+	// trail bytes: 0x40-0x7E and 0x80-0xFE
+	const loSize = 0xFF - 0x40 - 1
+
+	skip := 0
+	for skip < len(src) {
+		b := src[skip]
+		skip += 1
+
+		if b <= 0x7F {
+			result = append(result, rune(b))
+			continue
+		}
+
+		if (b == 0x80) || (b == 0xFF) || (skip >= len(src)) {
+			result = append(result, '�')
+			continue
+		}
+
+		hi := int(b)
+		lo := int(src[skip])
+
+		// four-byte code
+		if lo >= 0x30 && lo <= 0x39 {
+			if (skip+2 >= len(src)) ||
+				(src[skip+1] < 0x81) || (src[skip+1] > 0xFE) ||
+				(src[skip+2] < 0x30) || (src[skip+2] > 0x39) {
+				result = append(result, '�')
+				skip += 1
+				continue
+			}
+
+			pointer := (hi-0x81)*12600 +
+				(lo-0x30)*1260 +
+				(int(src[skip+1])-0x81)*10 +
+				(int(src[skip+2]) - 0x30)
+			skip += 3
+
+			switch {
+			case pointer < gb18030BMPSize:
+				result = append(result, gb18030RangeRune(pointer))
+			case pointer >= gb18030SupplementaryPointer &&
+				pointer < gb18030SupplementaryPointer+0x100000:
+				result = append(result, rune(0x10000+pointer-gb18030SupplementaryPointer))
+			default:
+				result = append(result, '�')
+			}
+
+			continue
+		}
+
+		skip += 1
+
+		switch {
+		case lo >= 0x40 && lo <= 0x7E:
+			lo -= 0x40
+		case lo >= 0x80 && lo <= 0xFE:
+			lo -= 0x41
+		default:
+			result = append(result, '�')
+			continue
+		}
+
+		mapSkip := ((hi - 0x81) * loSize) + lo
+
+		if r := decode_map_gb18030[mapSkip]; r != 0x0000 {
+			result = append(result, rune(r))
+		} else {
+			result = append(result, '�')
+		}
+	}
+
+	return string(result)
+}
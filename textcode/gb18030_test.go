@@ -0,0 +1,62 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGB18030(t *testing.T) {
+	utf8 := "中央电视台 €é\u0080😀￿"
+
+	coded := []byte{
+		0xD6, 0xD0, 0xD1, 0xEB, 0xB5, 0xE7, 0xCA, 0xD3, 0xCC, 0xA8, 0x20, 0xA2,
+		0xE3, 0xA8, 0xA6, 0x81, 0x30, 0x81, 0x30, 0x94, 0x39, 0xFC, 0x36, 0x84,
+		0x31, 0xA4, 0x39,
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		result := EncodeGB18030(utf8)
+		assert.Equal(t, coded, result)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		result := DecodeGB18030(coded)
+		assert.Equal(t, utf8, result)
+	})
+
+	t.Run("gb2312 compatible", func(t *testing.T) {
+		text := "天地玄黄 宇宙洪荒"
+		assert.Equal(t, EncodeGB2312(text), EncodeGB18030(text))
+	})
+
+	t.Run("out of bond", func(t *testing.T) {
+		result := DecodeGB18030([]byte{0x80, 0x81, 0x7F, 0x84, 0x31, 0xA5, 0x30})
+		assert.Equal(t, "���", result)
+	})
+}
+
+func TestGB18030_RoundTrip(t *testing.T) {
+	for r := rune(0x80); r <= 0xFFFF; r++ {
+		if r >= 0xD800 && r <= 0xDFFF {
+			continue
+		}
+
+		coded := EncodeGB18030(string(r))
+		if !assert.Equal(t, string(r), DecodeGB18030(coded), "U+%04X %X", r, coded) {
+			return
+		}
+	}
+
+	t.Run("private use area", func(t *testing.T) {
+		assert.Equal(t, []byte{0xAA, 0xA1}, EncodeGB18030("\uE000"))
+		assert.Equal(t, []byte{0xA1, 0x40}, EncodeGB18030("\uE4C6"))
+		assert.Equal(t, "\uE4C5", DecodeGB18030([]byte{0xFE, 0xFE}))
+		assert.Equal(t, []byte{0xA3, 0xA0}, EncodeGB18030("\uE5E5"))
+	})
+
+	t.Run("surrogate", func(t *testing.T) {
+		_, ok := appendGB18030(nil, 0xD800)
+		assert.False(t, ok)
+	})
+}
@@ -2,29 +2,36 @@ package textcode
 
 // EncodeGB2312 converts an UTF-8 string into GB2312 (Simplified Chinese)
 func EncodeGB2312(src string) []byte {
-	var result []byte
+	result, _ := tryEncodeGB2312(src)
+	return result
+}
 
-	for _, r := range src {
-		c := uint16(r)
+// tryEncodeGB2312 converts an UTF-8 string into GB2312.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeGB2312(src string) ([]byte, bool) {
+	return tryEncode(src, appendGB2312)
+}
 
-		if c <= 0x7F {
-			result = append(result, byte(c))
-			continue
-		}
+// appendGB2312 appends GB2312 code of the character. Returns false if not mapped
+func appendGB2312(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
 
-		hi := int(c >> 8)
-		lo := int(c & 0xFF)
+	code := uint16(0)
+
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
 		pos := (hi_map_gb2312[hi] * 0x100) + lo
-		code := encode_map_gb2312[pos]
+		code = encode_map_gb2312[pos]
+	}
 
-		if code != 0x0000 {
-			result = append(result, (byte(code >> 8)), (byte(code & 0xFF)))
-		} else {
-			result = append(result, '?')
-		}
+	if code == 0x0000 {
+		return dst, false
 	}
 
-	return result
+	return append(dst, (byte(code >> 8)), (byte(code & 0xFF))), true
 }
 
 // DecodeGB2312 converts GB2312 into UTF-8
@@ -3,32 +3,39 @@ package textcode
 // EncodeISO6937 converts an UTF-8 string into ISO-6937.
 // Latin superset of ISO/IEC 6937 with Euro and letters with diacritics.
 func EncodeISO6937(src string) []byte {
-	var result []byte
+	result, _ := tryEncodeISO6937(src)
+	return result
+}
 
-	for _, r := range src {
-		c := uint16(r)
+// tryEncodeISO6937 converts an UTF-8 string into ISO-6937.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeISO6937(src string) ([]byte, bool) {
+	return tryEncode(src, appendISO6937)
+}
 
-		if c <= 0x7F {
-			result = append(result, byte(c))
-			continue
-		}
+// appendISO6937 appends ISO-6937 code of the character. Returns false if not mapped
+func appendISO6937(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
 
-		hi := int(c >> 8)
-		lo := int(c & 0xFF)
-		pos := (hi_map_iso6937[hi] * 0x100) + lo
-		code := encode_map_iso6937[pos]
+	code := uint16(0)
 
-		switch {
-		case code > 0xFF:
-			result = append(result, (byte(code >> 8)), (byte(code & 0xFF)))
-		case code > 0:
-			result = append(result, byte(code))
-		default:
-			result = append(result, '?')
-		}
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
+		pos := (hi_map_iso6937[hi] * 0x100) + lo
+		code = encode_map_iso6937[pos]
 	}
 
-	return result
+	switch {
+	case code > 0xFF:
+		return append(dst, (byte(code >> 8)), (byte(code & 0xFF))), true
+	case code > 0:
+		return append(dst, byte(code)), true
+	default:
+		return dst, false
+	}
 }
 
 // DecodeISO6937 converts ISO-6937 into UTF-8.
@@ -1,33 +1,41 @@
 package textcode
 
 func encodeISO8859(src string, hiMap []int, m []uint8) []byte {
-	var result []byte
+	result, _ := tryEncodeISO8859(src, hiMap, m)
+	return result
+}
 
-	for _, r := range src {
-		c := uint16(r)
+// tryEncodeISO8859 converts an UTF-8 string into ISO-8859 charset.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeISO8859(src string, hiMap []int, m []uint8) ([]byte, bool) {
+	return tryEncode(src, func(dst []byte, r rune) ([]byte, bool) {
+		return appendISO8859(dst, r, hiMap, m)
+	})
+}
 
-		if c <= 0x7F {
-			result = append(result, byte(c))
-			continue
-		}
+// appendISO8859 appends ISO-8859 code of the character. Returns false if not mapped
+func appendISO8859(dst []byte, r rune, hiMap []int, m []uint8) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
+
+	code := uint8(0)
 
-		hi := int(c >> 8)
-		lo := int(c & 0xFF)
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
 		pos := (hiMap[hi] * 0x100) + lo
 
-		code := uint8(0)
 		if pos < len(m) {
 			code = m[pos]
 		}
+	}
 
-		if code != 0x00 {
-			result = append(result, code)
-		} else {
-			result = append(result, '?')
-		}
+	if code == 0x00 {
+		return dst, false
 	}
 
-	return result
+	return append(dst, code), true
 }
 
 // EncodeISO8859_1 converts an UTF-8 string into ISO-8859-1 (Western European)
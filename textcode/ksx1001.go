@@ -0,0 +1,77 @@
+package textcode
+
+// EncodeKSX1001 converts an UTF-8 string into KS X 1001 (Korean, EUC-KR)
+func EncodeKSX1001(src string) []byte {
+	result, _ := tryEncodeKSX1001(src)
+	return result
+}
+
+// tryEncodeKSX1001 converts an UTF-8 string into KS X 1001.
+// Not mapped characters replaced with '?'. Returns false if any.
+func tryEncodeKSX1001(src string) ([]byte, bool) {
+	return tryEncode(src, appendKSX1001)
+}
+
+// appendKSX1001 appends KS X 1001 code of the character. Returns false if not mapped
+func appendKSX1001(dst []byte, r rune) ([]byte, bool) {
+	if r <= 0x7F {
+		return append(dst, byte(r)), true
+	}
+
+	code := uint16(0)
+
+	if r <= 0xFFFF {
+		hi := int(r >> 8)
+		lo := int(r & 0xFF)
+		pos := (hi_map_ksx1001[hi] * 0x100) + lo
+		code = encode_map_ksx1001[pos]
+	}
+
+	if code == 0x0000 {
+		return dst, false
+	}
+
+	return append(dst, (byte(code >> 8)), (byte(code & 0xFF))), true
+}
+
+// DecodeKSX1001 converts KS X 1001 (Korean, EUC-KR) into UTF-8
+func DecodeKSX1001(src []byte) string {
+	var result []rune
+
+	const loSize = 0xFF - 0xA1
+
+	skip := 0
+	for skip < len(src) {
+		b := src[skip]
+		skip += 1
+
+		if b <= 0x7F {
+			result = append(result, rune(b))
+			continue
+		}
+
+		if skip >= len(src) {
+			result = append(result, '�')
+			break
+		}
+
+		hi := int(b)
+		lo := int(src[skip])
+		skip += 1
+
+		if (hi < 0xA1) || (hi > 0xFE) || (lo < 0xA1) || (lo > 0xFE) {
+			result = append(result, '�')
+			continue
+		}
+
+		mapSkip := ((hi - 0xA1) * loSize) + (lo - 0xA1)
+
+		if r := decode_map_ksx1001[mapSkip]; r != 0x0000 {
+			result = append(result, rune(r))
+		} else {
+			result = append(result, '�')
+		}
+	}
+
+	return string(result)
+}
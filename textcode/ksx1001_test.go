@@ -0,0 +1,36 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKSX1001(t *testing.T) {
+	utf8 := "안녕하세요 KBS1"
+
+	coded := []byte{
+		0xBE, 0xC8, 0xB3, 0xE7, 0xC7, 0xCF, 0xBC, 0xBC, 0xBF, 0xE4, 0x20, 0x4B,
+		0x42, 0x53, 0x31,
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		result := EncodeKSX1001(utf8)
+		assert.Equal(t, coded, result)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		result := DecodeKSX1001(coded)
+		assert.Equal(t, utf8, result)
+	})
+
+	t.Run("not mapped", func(t *testing.T) {
+		result := EncodeKSX1001("한ก")
+		assert.Equal(t, []byte{0xC7, 0xD1, '?'}, result)
+	})
+
+	t.Run("out of bond", func(t *testing.T) {
+		result := DecodeKSX1001([]byte{0xC7, 0x41, 0xC7})
+		assert.Equal(t, "��", result)
+	})
+}
@@ -0,0 +1,68 @@
+package textcode
+
+import (
+	"unicode/utf16"
+)
+
+// EncodeUTF16BE converts an UTF-8 string into UTF-16 big-endian.
+// Characters outside of the BMP are encoded with surrogate pairs.
+func EncodeUTF16BE(src string) []byte {
+	u := utf16.Encode([]rune(src))
+
+	result := make([]byte, 0, len(u)*2)
+	for _, c := range u {
+		result = append(result, byte(c>>8), byte(c))
+	}
+
+	return result
+}
+
+// appendUTF16BE appends UTF-16 big-endian code of the character.
+// Invalid code points are replaced with U+FFFD, so always returns true
+func appendUTF16BE(dst []byte, r rune) ([]byte, bool) {
+	if r1, r2 := utf16.EncodeRune(r); r1 != '\uFFFD' {
+		return append(dst, byte(r1>>8), byte(r1), byte(r2>>8), byte(r2)), true
+	}
+
+	if r > 0xFFFF || utf16.IsSurrogate(r) {
+		r = '\uFFFD'
+	}
+
+	return append(dst, byte(r>>8), byte(r)), true
+}
+
+// DecodeUTF16BE converts UTF-16 big-endian into UTF-8.
+// Odd trailing byte is ignored.
+func DecodeUTF16BE(src []byte) string {
+	u := make([]uint16, 0, len(src)/2)
+	for i := 0; i+1 < len(src); i += 2 {
+		u = append(u, uint16(src[i])<<8|uint16(src[i+1]))
+	}
+
+	return string(utf16.Decode(u))
+}
+
+// appendUCS2 appends ISO/IEC 10646 BMP code of the character (UCS-2 big-endian).
+// Returns false for characters outside of the BMP
+func appendUCS2(dst []byte, r rune) ([]byte, bool) {
+	if r > 0xFFFF || utf16.IsSurrogate(r) {
+		return dst, false
+	}
+
+	return append(dst, byte(r>>8), byte(r)), true
+}
+
+// decodeUCS2 converts ISO/IEC 10646 BMP (UCS-2 big-endian) into UTF-8.
+// Surrogates replaced with U+FFFD. Odd trailing byte is ignored.
+func decodeUCS2(src []byte) string {
+	result := make([]rune, 0, len(src)/2)
+	for i := 0; i+1 < len(src); i += 2 {
+		r := rune(src[i])<<8 | rune(src[i+1])
+		if utf16.IsSurrogate(r) {
+			r = '\uFFFD'
+		}
+		result = append(result, r)
+	}
+
+	return string(result)
+}
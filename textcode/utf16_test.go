@@ -0,0 +1,31 @@
+package textcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUTF16BE(t *testing.T) {
+	utf8 := "TV Пр 😀"
+
+	coded := []byte{
+		0x00, 0x54, 0x00, 0x56, 0x00, 0x20, 0x04, 0x1F, 0x04, 0x40, 0x00, 0x20,
+		0xD8, 0x3D, 0xDE, 0x00,
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		result := EncodeUTF16BE(utf8)
+		assert.Equal(t, coded, result)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		result := DecodeUTF16BE(coded)
+		assert.Equal(t, utf8, result)
+	})
+
+	t.Run("unpaired surrogate", func(t *testing.T) {
+		result := DecodeUTF16BE([]byte{0xD8, 0x3D, 0x00, 0x41, 0x00})
+		assert.Equal(t, "�A", result)
+	})
+}
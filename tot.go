@@ -0,0 +1,140 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cesbo/go-mpegts/crc32"
+)
+
+const (
+	TotHeaderSize  = 10
+	TotMaximumSize = 1024
+
+	// Maximum size of the descriptors to fit into the section
+	totDescriptorsMaximumSize = TotMaximumSize - TotHeaderSize - crc32.Size
+)
+
+// TOT is Time Offset Table.
+// Short section without section_syntax_indicator but with checksum.
+type TOT struct {
+	header []byte
+}
+
+var (
+	ErrTotFormat = errors.New("tot: invalid format")
+)
+
+var (
+	emptyTot = []byte{
+		0x73,                         // table_id
+		0x70,                         // section_length 1
+		0x00,                         // section_length 2
+		0xFF, 0xFF, 0xFF, 0xFF, 0xFF, // UTC_time
+		0xF0, // descriptors_loop_length 1
+		0x00, // descriptors_loop_length 2
+	}
+)
+
+func NewTot() *TOT {
+	t := new(TOT)
+	t.header = make([]byte, len(emptyTot))
+	copy(t.header, emptyTot)
+
+	return t
+}
+
+func (t *TOT) ParseTotSection(b []byte) error {
+	if len(b) < (TotHeaderSize+crc32.Size) || b[0] != 0x73 {
+		return ErrTotFormat
+	}
+
+	next := TotHeaderSize + int(binary.BigEndian.Uint16(b[8:])&0x0FFF)
+	if next > len(b)-crc32.Size {
+		return ErrTotFormat
+	}
+
+	desc := Descriptors(b[TotHeaderSize:next])
+	if err := desc.Check(); err != nil {
+		return fmt.Errorf("tot: %w", err)
+	}
+
+	t.header = make([]byte, next)
+	copy(t.header, b)
+
+	return nil
+}
+
+// Time returns UTC time
+func (t *TOT) Time() time.Time {
+	return decodeMJD(t.header[3:])
+}
+
+// SetTime sets UTC time. Time is truncated to seconds
+func (t *TOT) SetTime(value time.Time) {
+	encodeMJD(t.header[3:], value)
+}
+
+func (t *TOT) Descriptors() Descriptors {
+	return Descriptors(t.header[TotHeaderSize:])
+}
+
+// AppendDescriptors appends descriptors to the TOT descriptors.
+// Returns ErrDescriptorSize if descriptors do not fit into the section.
+func (t *TOT) AppendDescriptors(desc Descriptors) error {
+	if len(desc) == 0 {
+		return nil
+	}
+
+	ds := len(t.header) - TotHeaderSize + len(desc)
+	if ds > totDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+	t.header = append(t.header, desc...)
+
+	binary.BigEndian.PutUint16(t.header[8:], 0xF000|uint16(ds))
+	return nil
+}
+
+// SetDescriptors replaces TOT descriptors
+func (t *TOT) SetDescriptors(desc Descriptors) error {
+	if err := desc.Check(); err != nil {
+		return err
+	}
+
+	if len(desc) > totDescriptorsMaximumSize {
+		return ErrDescriptorSize
+	}
+
+	t.header = append(t.header[:TotHeaderSize:TotHeaderSize], desc...)
+
+	binary.BigEndian.PutUint16(t.header[8:], 0xF000|uint16(len(desc)))
+	return nil
+}
+
+// Packetizer returns a new PsiPacketizer to get TS packets from TOT
+func (t *TOT) Packetizer() *PsiPacketizer {
+	return newPsiPacketizer(t)
+}
+
+func (t *TOT) sectionSize(i int) int {
+	if i != -1 {
+		return 0
+	}
+
+	return len(t.header) + crc32.Size
+}
+
+func (t *TOT) sectionHeader(i int) []byte {
+	return t.header[:TotHeaderSize]
+}
+
+func (t *TOT) sectionItem(i int) []byte {
+	if i == -1 {
+		return t.header[TotHeaderSize:]
+	}
+
+	return nil
+}
@@ -0,0 +1,412 @@
+package mpegts
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ISO/IEC 13818-1 : 2.4.3.4 Adaptation field
+type AdaptationField struct {
+	Discontinuity bool // discontinuity_indicator
+	RandomAccess  bool // random_access_indicator
+	ESPriority    bool // elementary_stream_priority_indicator
+
+	HasPCR bool
+	PCR    PCR
+
+	HasOPCR bool
+	OPCR    PCR
+
+	HasSplicingPoint bool
+	SpliceCountdown  int8
+
+	// transport_private_data. nil if not present
+	PrivateData []byte
+
+	// adaptation_field_extension. nil if not present
+	Extension *AdaptationFieldExtension
+}
+
+// ISO/IEC 13818-1 : 2.4.3.4 adaptation_field_extension
+type AdaptationFieldExtension struct {
+	HasLTW    bool
+	LTWValid  bool   // ltw_valid_flag
+	LTWOffset uint16 // 15-bit legal time window offset
+
+	HasPiecewiseRate bool
+	PiecewiseRate    uint32 // 22-bit rate in units of 50 bytes/second
+
+	HasSeamlessSplice bool
+	SpliceType        uint8 // 4-bit splice_type
+	DTSNextAU         Timestamp
+}
+
+const (
+	afDiscontinuity = 0x80
+	afRandomAccess  = 0x40
+	afESPriority    = 0x20
+	afPCR           = 0x10
+	afOPCR          = 0x08
+	afSplicingPoint = 0x04
+	afPrivateData   = 0x02
+	afExtension     = 0x01
+	afMaximumSize   = PacketSize - 4 - 1
+	afExtLTW        = 0x80
+	afExtPiecewise  = 0x40
+	afExtSeamless   = 0x20
+	afExtReserved   = 0x1F
+)
+
+var (
+	ErrAdaptationField = errors.New("ts: invalid adaptation field")
+)
+
+// afFlag checks flag in the adaptation field flags byte.
+// Returns false if adaptation field is not present or empty.
+func (p TS) afFlag(flag byte) bool {
+	return p.HasAF() && p[4] != 0 && (p[5]&flag) != 0
+}
+
+// afSetFlag sets or clears flag in the adaptation field flags byte.
+// Returns false if adaptation field is not present or empty,
+// in this case packet is not modified.
+func (p TS) afSetFlag(flag byte, value bool) bool {
+	if !p.HasAF() || p[4] == 0 {
+		return false
+	}
+
+	if value {
+		p[5] |= flag
+	} else {
+		p[5] &^= flag
+	}
+
+	return true
+}
+
+// HasDiscontinuity checks the discontinuity_indicator in the Adaptation Field
+func (p TS) HasDiscontinuity() bool {
+	return p.afFlag(afDiscontinuity)
+}
+
+// SetDiscontinuity sets or clears the discontinuity_indicator.
+// Returns false if packet has no Adaptation Field or it is empty.
+func (p TS) SetDiscontinuity(value bool) bool {
+	return p.afSetFlag(afDiscontinuity, value)
+}
+
+// HasRandomAccess checks the random_access_indicator in the Adaptation Field
+func (p TS) HasRandomAccess() bool {
+	return p.afFlag(afRandomAccess)
+}
+
+// SetRandomAccess sets or clears the random_access_indicator.
+// Returns false if packet has no Adaptation Field or it is empty.
+func (p TS) SetRandomAccess(value bool) bool {
+	return p.afSetFlag(afRandomAccess, value)
+}
+
+// HasESPriority checks the elementary_stream_priority_indicator in the Adaptation Field
+func (p TS) HasESPriority() bool {
+	return p.afFlag(afESPriority)
+}
+
+// SetESPriority sets or clears the elementary_stream_priority_indicator.
+// Returns false if packet has no Adaptation Field or it is empty.
+func (p TS) SetESPriority(value bool) bool {
+	return p.afSetFlag(afESPriority, value)
+}
+
+// AdaptationField parses the packet Adaptation Field.
+// Returns nil if packet has no Adaptation Field.
+func (p TS) AdaptationField() (*AdaptationField, error) {
+	if !p.HasAF() {
+		return nil, nil
+	}
+
+	af := new(AdaptationField)
+	if err := af.Decode(p); err != nil {
+		return nil, err
+	}
+
+	return af, nil
+}
+
+// Decode parses Adaptation Field from the TS packet.
+// All fields are validated against adaptation_field_length.
+func (a *AdaptationField) Decode(p TS) error {
+	*a = AdaptationField{}
+
+	if len(p) < PacketSize || !p.HasAF() {
+		return ErrAdaptationField
+	}
+
+	size := int(p[4])
+	if size > afMaximumSize {
+		return ErrAdaptationField
+	}
+	if size == 0 {
+		return nil
+	}
+
+	end := 5 + size
+	flags := p[5]
+	skip := 6
+
+	a.Discontinuity = (flags & afDiscontinuity) != 0
+	a.RandomAccess = (flags & afRandomAccess) != 0
+	a.ESPriority = (flags & afESPriority) != 0
+
+	if (flags & afPCR) != 0 {
+		if skip+6 > end {
+			return ErrAdaptationField
+		}
+		a.HasPCR = true
+		a.PCR = getPCR(p[skip:])
+		skip += 6
+	}
+
+	if (flags & afOPCR) != 0 {
+		if skip+6 > end {
+			return ErrAdaptationField
+		}
+		a.HasOPCR = true
+		a.OPCR = getPCR(p[skip:])
+		skip += 6
+	}
+
+	if (flags & afSplicingPoint) != 0 {
+		if skip+1 > end {
+			return ErrAdaptationField
+		}
+		a.HasSplicingPoint = true
+		a.SpliceCountdown = int8(p[skip])
+		skip += 1
+	}
+
+	if (flags & afPrivateData) != 0 {
+		if skip+1 > end {
+			return ErrAdaptationField
+		}
+		next := skip + 1 + int(p[skip])
+		if next > end {
+			return ErrAdaptationField
+		}
+		a.PrivateData = make([]byte, next-skip-1)
+		copy(a.PrivateData, p[skip+1:next])
+		skip = next
+	}
+
+	if (flags & afExtension) != 0 {
+		if skip+1 > end {
+			return ErrAdaptationField
+		}
+		next := skip + 1 + int(p[skip])
+		if next > end {
+			return ErrAdaptationField
+		}
+		a.Extension = new(AdaptationFieldExtension)
+		if err := a.Extension.decode(p[skip+1 : next]); err != nil {
+			a.Extension = nil
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *AdaptationFieldExtension) decode(b []byte) error {
+	if len(b) < 1 {
+		return ErrAdaptationField
+	}
+
+	flags := b[0]
+	skip := 1
+
+	if (flags & afExtLTW) != 0 {
+		if skip+2 > len(b) {
+			return ErrAdaptationField
+		}
+		v := binary.BigEndian.Uint16(b[skip:])
+		e.HasLTW = true
+		e.LTWValid = (v & 0x8000) != 0
+		e.LTWOffset = v & 0x7FFF
+		skip += 2
+	}
+
+	if (flags & afExtPiecewise) != 0 {
+		if skip+3 > len(b) {
+			return ErrAdaptationField
+		}
+		e.HasPiecewiseRate = true
+		e.PiecewiseRate = (uint32(b[skip]&0x3F) << 16) |
+			(uint32(b[skip+1]) << 8) |
+			uint32(b[skip+2])
+		skip += 3
+	}
+
+	if (flags & afExtSeamless) != 0 {
+		if skip+5 > len(b) {
+			return ErrAdaptationField
+		}
+		e.HasSeamlessSplice = true
+		e.SpliceType = b[skip] >> 4
+		e.DTSNextAU = getTimestamp(b[skip:])
+	}
+
+	return nil
+}
+
+func (e *AdaptationFieldExtension) size() int {
+	size := 1
+	if e.HasLTW {
+		size += 2
+	}
+	if e.HasPiecewiseRate {
+		size += 3
+	}
+	if e.HasSeamlessSplice {
+		size += 5
+	}
+	return size
+}
+
+func (e *AdaptationFieldExtension) encode(b []byte) {
+	b[0] = afExtReserved
+	skip := 1
+
+	if e.HasLTW {
+		b[0] |= afExtLTW
+		v := e.LTWOffset & 0x7FFF
+		if e.LTWValid {
+			v |= 0x8000
+		}
+		binary.BigEndian.PutUint16(b[skip:], v)
+		skip += 2
+	}
+
+	if e.HasPiecewiseRate {
+		b[0] |= afExtPiecewise
+		b[skip] = 0xC0 | byte((e.PiecewiseRate>>16)&0x3F)
+		b[skip+1] = byte(e.PiecewiseRate >> 8)
+		b[skip+2] = byte(e.PiecewiseRate)
+		skip += 3
+	}
+
+	if e.HasSeamlessSplice {
+		b[0] |= afExtSeamless
+		setTimestamp(b[skip:], e.SpliceType<<4, e.DTSNextAU)
+	}
+}
+
+// Size returns number of bytes required for the Adaptation Field
+// including adaptation_field_length byte
+func (a *AdaptationField) Size() int {
+	size := 1 + 1 // adaptation_field_length + flags
+
+	if a.HasPCR {
+		size += 6
+	}
+	if a.HasOPCR {
+		size += 6
+	}
+	if a.HasSplicingPoint {
+		size += 1
+	}
+	if a.PrivateData != nil {
+		size += 1 + len(a.PrivateData)
+	}
+	if a.Extension != nil {
+		size += 1 + a.Extension.size()
+	}
+
+	return size
+}
+
+// Encode writes Adaptation Field into the TS packet right after the TS header
+// and sets Adaptation Field bit. PCR is written at the same offset as SetPCR does.
+// Returns offset to the payload begin.
+// Use Fill() to align payload to the end of packet with stuffing bytes.
+func (a *AdaptationField) Encode(p TS) (int, error) {
+	if len(a.PrivateData) > 0xFF {
+		return 0, ErrAdaptationField
+	}
+
+	size := a.Size()
+	if size > (PacketSize - 4) {
+		return 0, ErrAdaptationField
+	}
+
+	p.SetAF()
+	p[4] = byte(size - 1)
+
+	flags := byte(0)
+	if a.Discontinuity {
+		flags |= afDiscontinuity
+	}
+	if a.RandomAccess {
+		flags |= afRandomAccess
+	}
+	if a.ESPriority {
+		flags |= afESPriority
+	}
+
+	skip := 6
+
+	if a.HasPCR {
+		flags |= afPCR
+		setPCR(p[skip:], a.PCR)
+		skip += 6
+	}
+
+	if a.HasOPCR {
+		flags |= afOPCR
+		setPCR(p[skip:], a.OPCR)
+		skip += 6
+	}
+
+	if a.HasSplicingPoint {
+		flags |= afSplicingPoint
+		p[skip] = byte(a.SpliceCountdown)
+		skip += 1
+	}
+
+	if a.PrivateData != nil {
+		flags |= afPrivateData
+		p[skip] = byte(len(a.PrivateData))
+		skip += 1
+		skip += copy(p[skip:], a.PrivateData)
+	}
+
+	if a.Extension != nil {
+		flags |= afExtension
+		es := a.Extension.size()
+		p[skip] = byte(es)
+		skip += 1
+		a.Extension.encode(p[skip : skip+es])
+		skip += es
+	}
+
+	p[5] = flags
+
+	return skip, nil
+}
+
+// OPCR returns OPCR value from the Adaptation Field.
+// Returns NonPcr if OPCR is not defined or Adaptation Field is invalid.
+func (p TS) OPCR() PCR {
+	if !p.afFlag(afOPCR) {
+		return NonPcr
+	}
+
+	skip := 6
+	if (p[5] & afPCR) != 0 {
+		skip += 6
+	}
+
+	if skip+6 > 5+int(p[4]) {
+		return NonPcr
+	}
+
+	return getPCR(p[skip:])
+}
@@ -0,0 +1,174 @@
+package mpegts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptationField_Decode(t *testing.T) {
+	assert := assert.New(t)
+
+	packet := NewTS(256)
+	packet[3] = 0x30 // AF and payload
+	copy(packet[4:], []byte{
+		0x17,                               // AF length
+		0x76,                               // random access, ES priority, PCR, splicing point, private data
+		0x00, 0x02, 0x32, 0x89, 0x7E, 0xF7, // PCR
+		0xFD,                         // splice_countdown
+		0x04, 0xAA, 0xBB, 0xCC, 0xDD, // private data
+	})
+	for i := 4 + 1 + 13; i < 4+1+0x17; i++ {
+		packet[i] = 0xFF
+	}
+
+	af, err := packet.AdaptationField()
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.False(af.Discontinuity)
+	assert.True(af.RandomAccess)
+	assert.True(af.ESPriority)
+	assert.True(af.HasPCR)
+	assert.Equal(PCR(86405647), af.PCR)
+	assert.False(af.HasOPCR)
+	assert.True(af.HasSplicingPoint)
+	assert.Equal(int8(-3), af.SpliceCountdown)
+	assert.Equal([]byte{0xAA, 0xBB, 0xCC, 0xDD}, af.PrivateData)
+	assert.Nil(af.Extension)
+
+	assert.True(packet.HasRandomAccess())
+	assert.True(packet.HasESPriority())
+	assert.False(packet.HasDiscontinuity())
+	assert.Equal(NonPcr, packet.OPCR())
+}
+
+func TestAdaptationField_DecodeErrors(t *testing.T) {
+	t.Run("pcr out of range", func(t *testing.T) {
+		packet := NewTS(256)
+		packet[3] = 0x30
+		packet[4] = 4
+		packet[5] = 0x10
+
+		_, err := packet.AdaptationField()
+		assert.ErrorIs(t, err, ErrAdaptationField)
+	})
+
+	t.Run("private data out of range", func(t *testing.T) {
+		packet := NewTS(256)
+		packet[3] = 0x30
+		packet[4] = 4
+		packet[5] = 0x02
+		packet[6] = 10
+
+		_, err := packet.AdaptationField()
+		assert.ErrorIs(t, err, ErrAdaptationField)
+	})
+
+	t.Run("length out of range", func(t *testing.T) {
+		packet := NewTS(256)
+		packet[3] = 0x20
+		packet[4] = 184
+
+		_, err := packet.AdaptationField()
+		assert.ErrorIs(t, err, ErrAdaptationField)
+	})
+}
+
+func TestAdaptationField_Encode(t *testing.T) {
+	assert := assert.New(t)
+
+	af := AdaptationField{
+		Discontinuity:    true,
+		RandomAccess:     true,
+		HasPCR:           true,
+		PCR:              2268366350823,
+		HasOPCR:          true,
+		OPCR:             86405647,
+		HasSplicingPoint: true,
+		SpliceCountdown:  5,
+		PrivateData:      []byte{0x01, 0x02, 0x03},
+		Extension: &AdaptationFieldExtension{
+			HasLTW:            true,
+			LTWValid:          true,
+			LTWOffset:         0x1234,
+			HasPiecewiseRate:  true,
+			PiecewiseRate:     0x123456,
+			HasSeamlessSplice: true,
+			SpliceType:        3,
+			DTSNextAU:         5893093935,
+		},
+	}
+
+	packet := NewTS(256)
+	packet.SetPayload()
+
+	skip, err := af.Encode(packet)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(4+af.Size(), skip)
+	assert.Equal(skip, packet.HeaderSize())
+
+	// PCR is at the same offset as SetPCR writes
+	assert.True(packet.HasPCR())
+	assert.Equal(PCR(2268366350823), packet.PCR())
+	assert.Equal(PCR(86405647), packet.OPCR())
+
+	for i := skip; i < skip+10; i++ {
+		packet[i] = byte(i)
+	}
+	packet.Fill(skip + 10)
+
+	decoded, err := packet.AdaptationField()
+	if !assert.NoError(err) {
+		return
+	}
+	assert.Equal(&af, decoded)
+	assert.Equal(10, len(packet.Payload()))
+}
+
+func TestTS_SetRandomAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	packet := NewTS(256)
+	packet[3] = 0x30
+	packet[4] = 7
+	packet[5] = 0x00
+	packet.SetPCR(86405647)
+
+	assert.True(packet.SetRandomAccess(true))
+	assert.True(packet.SetDiscontinuity(true))
+	assert.Equal(TS{0xD0, 0x00, 0x02, 0x32, 0x89, 0x7E, 0xF7}, packet[5:12])
+
+	assert.True(packet.SetDiscontinuity(false))
+	assert.True(packet.HasRandomAccess())
+	assert.False(packet.HasDiscontinuity())
+	assert.Equal(PCR(86405647), packet.PCR())
+}
+
+func TestTS_SetRandomAccess_NoAF(t *testing.T) {
+	assert := assert.New(t)
+
+	// payload only
+	packet := NewTS(256)
+	for i := 4; i < PacketSize; i++ {
+		packet[i] = byte(i)
+	}
+	expected := append(TS{}, packet...)
+
+	assert.False(packet.SetRandomAccess(true))
+	assert.False(packet.SetDiscontinuity(true))
+	assert.False(packet.SetESPriority(false))
+	assert.Equal(expected, packet)
+	assert.False(packet.HasRandomAccess())
+
+	// empty adaptation field
+	packet = NewTS(256)
+	packet.Fill(PacketSize - 1)
+	expected = append(TS{}, packet...)
+
+	assert.False(packet.SetRandomAccess(true))
+	assert.Equal(expected, packet)
+}
@@ -0,0 +1,225 @@
+package mpegts
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	// Number of packets in the PacketReader buffer
+	readerBufferPackets = 64
+
+	// Number of packets in the UDP/RTP payload
+	UdpPackets = 7
+)
+
+// PacketReader reads TS packets from io.Reader.
+// Internal buffer is reused between calls.
+type PacketReader struct {
+	reader io.Reader
+	slicer *Slicer
+	buffer []byte
+
+	started bool
+	lost    bool // sync error since last packet
+	err     error
+}
+
+// PacketWriter aggregates TS packets into chunks and writes them to io.Writer
+type PacketWriter struct {
+	writer io.Writer
+	slicer Slicer
+	buffer []byte
+	fill   int
+}
+
+var (
+	ErrShortBuffer = errors.New("ts reader: buffer less than packet size")
+	ErrShortPacket = errors.New("ts writer: packet less than packet size")
+)
+
+// NewPacketReader returns a new PacketReader for 188-byte packets
+func NewPacketReader(r io.Reader) *PacketReader {
+	return NewPacketReaderSlicer(r, new(Slicer))
+}
+
+// NewPacketReaderSlicer returns a new PacketReader with custom Slicer.
+// For example Slicer with packet size detection or resync mode.
+func NewPacketReaderSlicer(r io.Reader, slicer *Slicer) *PacketReader {
+	return &PacketReader{
+		reader: r,
+		slicer: slicer,
+		buffer: make([]byte, RSPacketSize*readerBufferPackets),
+	}
+}
+
+// Slicer returns Slicer used by the reader
+func (r *PacketReader) Slicer() *Slicer {
+	return r.slicer
+}
+
+// ReadPacket returns next TS packet.
+// Packet is valid until next call of ReadPacket or ReadPackets.
+// Returns io.EOF if no more packets.
+// Returns io.ErrUnexpectedEOF if stream ends with incomplete packet.
+// Returns ErrSyncTS if stream ends with data without TS packets.
+func (r *PacketReader) ReadPacket() (TS, error) {
+	for {
+		if r.started {
+			if packet := r.check(r.slicer.Next()); packet != nil {
+				return packet, nil
+			}
+			r.started = false
+		}
+
+		if r.err != nil {
+			// all data is processed by the slicer
+			if r.err == io.EOF {
+				if r.lost {
+					r.err = ErrSyncTS
+				} else if r.slicer.fill != 0 || len(r.slicer.probe) != 0 {
+					r.err = io.ErrUnexpectedEOF
+				}
+			}
+
+			return nil, r.err
+		}
+
+		n, err := r.reader.Read(r.buffer)
+		if err != nil {
+			r.err = err
+		}
+
+		if n > 0 {
+			if packet := r.check(r.slicer.Begin(r.buffer[:n])); packet != nil {
+				r.started = true
+				return packet, nil
+			}
+		}
+	}
+}
+
+// check keeps sync error state till the next packet
+func (r *PacketReader) check(packet TS) TS {
+	if packet != nil {
+		r.lost = false
+	} else if r.slicer.Err() == ErrSyncTS {
+		r.lost = true
+	}
+
+	return packet
+}
+
+// ReadPackets reads TS packets and copies them into b.
+// Returns number of packets. Length of b should be not less than PacketSize.
+// Returns io.EOF if no more packets.
+func (r *PacketReader) ReadPackets(b []byte) (int, error) {
+	limit := len(b) / PacketSize
+	if limit == 0 {
+		return 0, ErrShortBuffer
+	}
+
+	count := 0
+	for count < limit {
+		packet, err := r.ReadPacket()
+		if err != nil {
+			if count != 0 && err == io.EOF {
+				return count, nil
+			}
+			return count, err
+		}
+
+		copy(b[count*PacketSize:], packet)
+		count += 1
+	}
+
+	return count, nil
+}
+
+// NewPacketWriter returns a new PacketWriter.
+// count is a number of packets in the chunk, for example UdpPackets.
+func NewPacketWriter(w io.Writer, count int) *PacketWriter {
+	if count < 1 {
+		count = 1
+	}
+
+	return &PacketWriter{
+		writer: w,
+		buffer: make([]byte, count*PacketSize),
+	}
+}
+
+// WritePacket appends packet to the chunk.
+// Writes chunk if it is completed.
+// Returns ErrShortPacket if packet is less than PacketSize.
+// On write error chunk remains in the buffer and will be written
+// before the next packet.
+func (w *PacketWriter) WritePacket(packet TS) error {
+	if len(packet) < PacketSize {
+		return ErrShortPacket
+	}
+
+	if w.fill == len(w.buffer) {
+		// chunk is not written on previous call
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	w.fill += copy(w.buffer[w.fill:], packet[:PacketSize])
+
+	if w.fill == len(w.buffer) {
+		return w.Flush()
+	}
+
+	return nil
+}
+
+// Write implements io.Writer.
+// Splits b to TS packets with Slicer and appends them to the chunk.
+// Incomplete packet remains in the Slicer buffer till next call.
+// On error returns number of bytes consumed by the Slicer.
+// Returns ErrSyncTS if b has no TS packets.
+func (w *PacketWriter) Write(b []byte) (int, error) {
+	if w.fill == len(w.buffer) {
+		if err := w.Flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	for packet := w.slicer.Begin(b); packet != nil; packet = w.slicer.Next() {
+		if err := w.WritePacket(packet); err != nil {
+			return w.slicer.skip, err
+		}
+	}
+
+	if err := w.slicer.Err(); err == ErrSyncTS {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// Flush writes incomplete chunk.
+// On error unwritten data remains in the buffer.
+func (w *PacketWriter) Flush() error {
+	if w.fill == 0 {
+		return nil
+	}
+
+	n, err := w.writer.Write(w.buffer[:w.fill])
+	if err == nil && n != w.fill {
+		err = io.ErrShortWrite
+	}
+
+	if err != nil {
+		if n > 0 && n < w.fill {
+			w.fill = copy(w.buffer, w.buffer[n:w.fill])
+		}
+		return err
+	}
+
+	w.fill = 0
+
+	return nil
+}
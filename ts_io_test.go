@@ -0,0 +1,284 @@
+package mpegts
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExamplePacketReader() {
+	reader := NewPacketReader(bytes.NewReader(NullTS))
+
+	for {
+		packet, err := reader.ReadPacket()
+		if err != nil {
+			break
+		}
+		fmt.Println("PID", packet.PID())
+	}
+
+	// Output:
+	// PID 8191
+}
+
+func TestPacketReader_ReadPacket(t *testing.T) {
+	stream := makeSlicerStream(PacketSize, 100)
+
+	t.Run("full", func(t *testing.T) {
+		assert := assert.New(t)
+
+		reader := NewPacketReader(bytes.NewReader(stream))
+		count := 0
+		for {
+			packet, err := reader.ReadPacket()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(uint8(count&0x0F), packet.CC())
+			count += 1
+		}
+		assert.Equal(100, count)
+	})
+
+	t.Run("one byte reader", func(t *testing.T) {
+		assert := assert.New(t)
+
+		reader := NewPacketReader(iotest.OneByteReader(bytes.NewReader(stream)))
+		count := 0
+		for {
+			packet, err := reader.ReadPacket()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(uint8(count&0x0F), packet.CC())
+			count += 1
+		}
+		assert.Equal(100, count)
+	})
+
+	t.Run("unexpected eof", func(t *testing.T) {
+		assert := assert.New(t)
+
+		reader := NewPacketReader(bytes.NewReader(stream[:PacketSize+10]))
+
+		_, err := reader.ReadPacket()
+		assert.NoError(err)
+
+		_, err = reader.ReadPacket()
+		assert.ErrorIs(err, io.ErrUnexpectedEOF)
+
+		// EOF with the last data
+		reader = NewPacketReader(iotest.DataErrReader(bytes.NewReader(stream[:PacketSize+10])))
+
+		_, err = reader.ReadPacket()
+		assert.NoError(err)
+
+		_, err = reader.ReadPacket()
+		assert.ErrorIs(err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("sync error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		reader := NewPacketReader(bytes.NewReader(make([]byte, PacketSize*4)))
+
+		_, err := reader.ReadPacket()
+		assert.ErrorIs(err, ErrSyncTS)
+
+		// garbage in the end of stream
+		garbage := append(append([]byte{}, stream[:PacketSize]...), make([]byte, PacketSize*4)...)
+		reader = NewPacketReader(iotest.OneByteReader(bytes.NewReader(garbage)))
+
+		_, err = reader.ReadPacket()
+		assert.NoError(err)
+
+		_, err = reader.ReadPacket()
+		assert.ErrorIs(err, ErrSyncTS)
+	})
+
+	t.Run("m2ts", func(t *testing.T) {
+		assert := assert.New(t)
+
+		m2ts := makeSlicerStream(M2TSPacketSize, 100)
+		slicer, err := NewSlicer(0)
+		assert.NoError(err)
+
+		reader := NewPacketReaderSlicer(bytes.NewReader(m2ts), slicer)
+		count := 0
+		for {
+			packet, err := reader.ReadPacket()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(err) {
+				return
+			}
+			assert.Equal(uint8(count&0x0F), packet.CC())
+			assert.Equal(uint32(count), reader.Slicer().ArrivalTimestamp())
+			count += 1
+		}
+		assert.Equal(100, count)
+	})
+}
+
+func TestPacketReader_ReadPackets(t *testing.T) {
+	assert := assert.New(t)
+
+	stream := makeSlicerStream(PacketSize, 10)
+	reader := NewPacketReader(iotest.HalfReader(bytes.NewReader(stream)))
+	buffer := make([]byte, PacketSize*UdpPackets)
+
+	n, err := reader.ReadPackets(buffer)
+	assert.NoError(err)
+	assert.Equal(UdpPackets, n)
+	assert.Equal(stream[:n*PacketSize], buffer)
+
+	n, err = reader.ReadPackets(buffer)
+	assert.NoError(err)
+	assert.Equal(3, n)
+	assert.Equal(stream[UdpPackets*PacketSize:], buffer[:n*PacketSize])
+
+	n, err = reader.ReadPackets(buffer)
+	assert.ErrorIs(err, io.EOF)
+	assert.Equal(0, n)
+
+	_, err = reader.ReadPackets(buffer[:10])
+	assert.ErrorIs(err, ErrShortBuffer)
+}
+
+type chunkRecorder struct {
+	chunks [][]byte
+	err    error
+}
+
+func (c *chunkRecorder) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	chunk := make([]byte, len(b))
+	copy(chunk, b)
+	c.chunks = append(c.chunks, chunk)
+	return len(b), nil
+}
+
+func TestPacketWriter(t *testing.T) {
+	stream := makeSlicerStream(PacketSize, 10)
+
+	t.Run("write packet", func(t *testing.T) {
+		assert := assert.New(t)
+
+		recorder := new(chunkRecorder)
+		writer := NewPacketWriter(recorder, UdpPackets)
+
+		for i := 0; i < 10; i++ {
+			packet := TS(stream[i*PacketSize : (i+1)*PacketSize])
+			assert.NoError(writer.WritePacket(packet))
+		}
+
+		assert.Equal(1, len(recorder.chunks))
+		assert.NoError(writer.Flush())
+
+		if assert.Equal(2, len(recorder.chunks)) {
+			assert.Equal(stream[:UdpPackets*PacketSize], recorder.chunks[0])
+			assert.Equal(stream[UdpPackets*PacketSize:], recorder.chunks[1])
+		}
+	})
+
+	t.Run("write", func(t *testing.T) {
+		assert := assert.New(t)
+
+		recorder := new(chunkRecorder)
+		writer := NewPacketWriter(recorder, 2)
+
+		for skip := 0; skip < len(stream); skip += 100 {
+			end := skip + 100
+			if end > len(stream) {
+				end = len(stream)
+			}
+
+			n, err := writer.Write(stream[skip:end])
+			assert.NoError(err)
+			assert.Equal(end-skip, n)
+		}
+		assert.NoError(writer.Flush())
+
+		assert.Equal(5, len(recorder.chunks))
+		assert.Equal(stream, bytes.Join(recorder.chunks, nil))
+	})
+	t.Run("write error", func(t *testing.T) {
+		assert := assert.New(t)
+
+		recorder := &chunkRecorder{err: io.ErrClosedPipe}
+		writer := NewPacketWriter(recorder, 2)
+
+		n, err := writer.Write(stream[:PacketSize*3+10])
+		assert.ErrorIs(err, io.ErrClosedPipe)
+		assert.Equal(PacketSize*2, n)
+
+		n, err = writer.Write(stream[PacketSize*2:])
+		assert.ErrorIs(err, io.ErrClosedPipe)
+		assert.Equal(0, n)
+
+		// chunk is kept and written on the next call
+		recorder.err = nil
+		n, err = writer.Write(make([]byte, PacketSize))
+		assert.ErrorIs(err, ErrSyncTS)
+		assert.Equal(0, n)
+
+		if assert.Equal(1, len(recorder.chunks)) {
+			assert.Equal(stream[:PacketSize*2], recorder.chunks[0])
+		}
+	})
+
+	t.Run("short write", func(t *testing.T) {
+		assert := assert.New(t)
+
+		recorder := &shortRecorder{limit: PacketSize + 10}
+		writer := NewPacketWriter(recorder, 2)
+
+		assert.NoError(writer.WritePacket(TS(stream[:PacketSize])))
+		assert.ErrorIs(writer.WritePacket(TS(stream[PacketSize:PacketSize*2])), io.ErrShortWrite)
+
+		recorder.limit = 0
+		assert.NoError(writer.WritePacket(TS(stream[PacketSize*2 : PacketSize*3])))
+		assert.NoError(writer.Flush())
+		assert.Equal(stream[:PacketSize*3], recorder.data)
+	})
+
+	t.Run("short packet", func(t *testing.T) {
+		assert := assert.New(t)
+
+		recorder := new(chunkRecorder)
+		writer := NewPacketWriter(recorder, 2)
+
+		assert.ErrorIs(writer.WritePacket(TS(stream[:PacketSize-1])), ErrShortPacket)
+		assert.NoError(writer.Flush())
+		assert.Equal(0, len(recorder.chunks))
+	})
+}
+
+// shortRecorder writes not more than limit bytes per call. 0 is unlimited
+type shortRecorder struct {
+	data  []byte
+	limit int
+}
+
+func (r *shortRecorder) Write(b []byte) (int, error) {
+	if r.limit != 0 && len(b) > r.limit {
+		b = b[:r.limit]
+	}
+
+	r.data = append(r.data, b...)
+	return len(b), nil
+}
@@ -25,31 +25,42 @@ func (p TS) HasPCR() bool {
 // SetPCR sets PCR flag and PCR value in the Adaptation Field.
 func (p TS) SetPCR(value PCR) {
 	p[5] |= 0x10 // PCR_flag
-
-	pcrBase := value / 300
-	pcrExt := value - (pcrBase * 300)
-
-	p[6] = byte(pcrBase >> 25)
-	p[7] = byte(pcrBase >> 17)
-	p[8] = byte(pcrBase >> 9)
-	p[9] = byte(pcrBase >> 1)
-	p[10] = (byte((pcrBase << 7) & 0x80)) | 0x7E | (byte((pcrExt >> 8) & 0x01))
-	p[11] = byte(pcrExt)
+	setPCR(p[6:], value)
 }
 
 // PCR returns PCR value from the Adaptation Field.
 // Packet should be with Adaptation Field
 func (p TS) PCR() PCR {
-	pcrBase := (PCR(p[6]) << 25) |
-		(PCR(p[7]) << 17) |
-		(PCR(p[8]) << 9) |
-		(PCR(p[9]) << 1) |
-		(PCR(p[10]) >> 7)
-	pcrExt := (PCR((p[10] & 1)) << 8) | PCR(p[11])
+	return getPCR(p[6:])
+}
+
+// getPCR returns PCR value from the 6 bytes of program_clock_reference field
+func getPCR(b []byte) PCR {
+	_ = b[5]
+	pcrBase := (PCR(b[0]) << 25) |
+		(PCR(b[1]) << 17) |
+		(PCR(b[2]) << 9) |
+		(PCR(b[3]) << 1) |
+		(PCR(b[4]) >> 7)
+	pcrExt := (PCR((b[4] & 1)) << 8) | PCR(b[5])
 
 	return (pcrBase * 300) + pcrExt
 }
 
+// setPCR puts PCR value into the 6 bytes of program_clock_reference field
+func setPCR(b []byte, value PCR) {
+	_ = b[5]
+	pcrBase := value / 300
+	pcrExt := value - (pcrBase * 300)
+
+	b[0] = byte(pcrBase >> 25)
+	b[1] = byte(pcrBase >> 17)
+	b[2] = byte(pcrBase >> 9)
+	b[3] = byte(pcrBase >> 1)
+	b[4] = (byte((pcrBase << 7) & 0x80)) | 0x7E | (byte((pcrExt >> 8) & 0x01))
+	b[5] = byte(pcrExt)
+}
+
 // Delta returns the difference p-previous considering value overflow
 func (p PCR) Delta(previous PCR) PCR {
 	if p >= previous {
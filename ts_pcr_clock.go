@@ -0,0 +1,270 @@
+package mpegts
+
+import (
+	"errors"
+	"math/bits"
+)
+
+// PCRClock tracks Program Clock Reference of the program
+// and assigns PCR value to each packet of the stream.
+// Push buffers packets until the next PCR and interpolates
+// PCR values between two real PCR values.
+// Update returns value immediately, extrapolated from the last PCR
+// with the bitrate measured on the previous PCR interval,
+// so values drift if bitrate changes.
+type PCRClock struct {
+	pid PID
+
+	position uint64 // bytes since the first packet
+	current  PCR    // PCR of the last packet
+
+	started bool
+	pcr     PCR    // last real PCR
+	offset  uint64 // position of the last real PCR
+
+	delta PCR    // PCR delta on the previous interval
+	block uint64 // bytes on the previous interval
+
+	pending   []byte // packets pushed after the last real PCR
+	estimated []PCR  // extrapolated PCR values of the pending packets
+}
+
+// PCRClockFn is a PCRClock callback with packet and its PCR value.
+// PCR is NonPcr if value is not defined.
+// Packet is valid till PCRClockFn returns.
+type PCRClockFn func(packet TS, pcr PCR)
+
+// PCRRestamper rewrites PCR values in the output stream
+// according to the packet position and constant output bitrate.
+// Used to keep PCR accuracy after remux: packets removing,
+// inserting, or delay.
+type PCRRestamper struct {
+	bitrate uint64
+
+	started  bool
+	base     PCR    // PCR on the base position
+	position uint64 // bytes since the base position
+}
+
+var (
+	ErrPcrBitrate = errors.New("pcr restamper: invalid bitrate")
+)
+
+const (
+	// Maximum interval between two PCR values to measure bitrate.
+	// ISO/IEC 13818-1 requires interval not more than 100ms.
+	pcrClockMaxDelta PCR = ProgramClock
+
+	// Maximum bytes to buffer till the next PCR.
+	// 1 second on 100 Mbit/s
+	pcrClockMaxPending = 100e6 / 8
+)
+
+// hasPCR checks PCR flag and size of the Adaptation Field
+func (p TS) hasPCR() bool {
+	return p.afFlag(afPCR) && p[4] >= 7
+}
+
+// NewPCRClock returns a new PCRClock for PCR_PID of the program
+func NewPCRClock(pid PID) *PCRClock {
+	return &PCRClock{
+		pid:     pid,
+		current: NonPcr,
+	}
+}
+
+// Reset clears clock state. Pending packets are dropped.
+func (c *PCRClock) Reset() {
+	*c = PCRClock{
+		pid:       c.pid,
+		current:   NonPcr,
+		pending:   c.pending[:0],
+		estimated: c.estimated[:0],
+	}
+}
+
+// Update processes next packet of the stream.
+// Should be called for each packet of the stream, for all PIDs, in order.
+// Returns PCR value for the packet or NonPcr if clock is not ready yet:
+// at least two PCR values required to extrapolate clock.
+func (c *PCRClock) Update(packet TS) PCR {
+	position := c.position
+	c.position += uint64(PacketSize)
+
+	if packet.PID() == c.pid && packet.hasPCR() {
+		pcr := packet.PCR()
+
+		c.delta = 0
+		c.block = 0
+
+		if c.started && !packet.HasDiscontinuity() {
+			delta := pcr.Delta(c.pcr)
+			if delta != 0 && delta <= pcrClockMaxDelta {
+				c.delta = delta
+				c.block = position - c.offset
+			}
+		}
+
+		c.started = true
+		c.pcr = pcr
+		c.offset = position
+		c.current = pcr
+
+		return pcr
+	}
+
+	if c.block == 0 {
+		c.current = NonPcr
+		return NonPcr
+	}
+
+	stc := uint64(c.delta) * (position - c.offset) / c.block
+	c.current = c.pcr.Add(PCR(stc % uint64(NonPcr)))
+
+	return c.current
+}
+
+// Push processes next packet of the stream and calls fn for each packet
+// with PCR value interpolated between two real PCR values:
+// pcr0 + (pcr1 - pcr0) * offset / span.
+// Should be called for each packet of the stream, for all PIDs, in order.
+// Packets are buffered till the next PCR, fn is called for packets in order.
+// Packets before the first PCR or after discontinuity have NonPcr value.
+// Should not be mixed with Update.
+func (c *PCRClock) Push(packet TS, fn PCRClockFn) {
+	if packet.PID() != c.pid || !packet.hasPCR() {
+		estimated := c.Update(packet)
+		if !c.started {
+			fn(packet, NonPcr)
+			return
+		}
+
+		c.pending = append(c.pending, packet[:PacketSize]...)
+		c.estimated = append(c.estimated, estimated)
+
+		if len(c.pending) >= pcrClockMaxPending {
+			// PCR is lost
+			c.Flush(fn)
+		}
+
+		return
+	}
+
+	pcr := c.pcr
+	current := c.Update(packet)
+
+	// c.delta and c.block are defined for the interval
+	// between previous and current PCR
+	for i := range c.estimated {
+		value := NonPcr
+
+		if c.block != 0 {
+			// pending packet position since the previous PCR
+			position := uint64(i+1) * uint64(PacketSize)
+			value = pcr.Add(PCR(uint64(c.delta) * position / c.block))
+		}
+
+		skip := i * PacketSize
+		fn(TS(c.pending[skip:skip+PacketSize]), value)
+	}
+
+	c.pending = c.pending[:0]
+	c.estimated = c.estimated[:0]
+
+	fn(packet, current)
+}
+
+// Flush calls fn for each packet buffered by Push
+// with PCR value extrapolated from the last PCR.
+// Should be called at the end of the stream.
+func (c *PCRClock) Flush(fn PCRClockFn) {
+	for i, value := range c.estimated {
+		skip := i * PacketSize
+		fn(TS(c.pending[skip:skip+PacketSize]), value)
+	}
+
+	c.pending = c.pending[:0]
+	c.estimated = c.estimated[:0]
+}
+
+// PCR returns PCR value of the last packet or NonPcr if clock is not ready
+func (c *PCRClock) PCR() PCR {
+	return c.current
+}
+
+// Bitrate returns stream bitrate in bits per second measured on the
+// last PCR interval. Returns 0 if clock is not ready.
+func (c *PCRClock) Bitrate() int {
+	if c.block == 0 {
+		return 0
+	}
+
+	return c.delta.Bitrate(int(c.block))
+}
+
+// NewPCRRestamper returns a new PCRRestamper for output bitrate
+// in bits per second. Returns ErrPcrBitrate if bitrate is not positive.
+func NewPCRRestamper(bitrate int) (*PCRRestamper, error) {
+	r := new(PCRRestamper)
+	if err := r.SetBitrate(bitrate); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reset clears restamper state.
+// Next packet with PCR defines a new base value.
+func (r *PCRRestamper) Reset() {
+	r.started = false
+	r.base = 0
+	r.position = 0
+}
+
+// SetBitrate changes output bitrate.
+// PCR values before current position keep previous bitrate.
+// Returns ErrPcrBitrate if bitrate is not positive.
+func (r *PCRRestamper) SetBitrate(bitrate int) error {
+	if bitrate <= 0 {
+		return ErrPcrBitrate
+	}
+
+	if r.started {
+		r.base = r.PCR()
+		r.position = 0
+	}
+
+	r.bitrate = uint64(bitrate)
+	return nil
+}
+
+// PCR returns PCR value for the current output position.
+// Returns NonPcr if restamper has no base value yet.
+func (r *PCRRestamper) PCR() PCR {
+	if !r.started {
+		return NonPcr
+	}
+
+	hi, lo := bits.Mul64(r.position, 8*ProgramClock)
+	stc, _ := bits.Div64(hi, lo, r.bitrate)
+
+	return r.base.Add(PCR(stc % uint64(NonPcr)))
+}
+
+// Restamp processes next packet of the output stream.
+// Should be called for each output packet, for all PIDs, in order.
+// First PCR in the stream or PCR with discontinuity_indicator
+// defines base value, next PCR values are rewritten.
+func (r *PCRRestamper) Restamp(packet TS) {
+	if packet.hasPCR() {
+		if !r.started || packet.HasDiscontinuity() {
+			r.started = true
+			r.base = packet.PCR()
+			r.position = 0
+		} else {
+			packet.SetPCR(r.PCR())
+		}
+	}
+
+	r.position += uint64(PacketSize)
+}
@@ -0,0 +1,194 @@
+package mpegts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makePcrPacket(pid PID, pcr PCR) TS {
+	packet := NewTS(pid)
+	af := AdaptationField{HasPCR: true, PCR: pcr}
+	af.Encode(packet)
+	return packet
+}
+
+func TestPCRClock_Update(t *testing.T) {
+	assert := assert.New(t)
+
+	clock := NewPCRClock(100)
+	data := NewTS(200)
+
+	// 10 packets per 1000 ticks
+	assert.Equal(PCR(1000), clock.Update(makePcrPacket(100, 1000)))
+	assert.Equal(NonPcr, clock.Update(data))
+
+	for i := 0; i < 8; i++ {
+		assert.Equal(NonPcr, clock.Update(data))
+	}
+
+	assert.Equal(PCR(2000), clock.Update(makePcrPacket(100, 2000)))
+	assert.Equal(PacketSize*10*8*ProgramClock/1000, clock.Bitrate())
+
+	for i := 1; i < 10; i++ {
+		assert.Equal(PCR(2000+i*100), clock.Update(data), "packet #%d", i)
+	}
+	assert.Equal(PCR(2900), clock.PCR())
+
+	// PCR on other PID is ignored
+	assert.Equal(PCR(3000), clock.Update(makePcrPacket(101, 50)))
+
+	// discontinuity
+	packet := makePcrPacket(100, 500)
+	packet.SetDiscontinuity(true)
+	assert.Equal(PCR(500), clock.Update(packet))
+	assert.Equal(NonPcr, clock.Update(data))
+	assert.Equal(0, clock.Bitrate())
+}
+
+func TestPCRClock_Overflow(t *testing.T) {
+	assert := assert.New(t)
+
+	clock := NewPCRClock(100)
+	data := NewTS(200)
+
+	clock.Update(makePcrPacket(100, MaxPcr-1499))
+	clock.Update(data)
+	clock.Update(makePcrPacket(100, MaxPcr-499))
+	assert.Equal(PCR(0), clock.Update(data))
+	assert.Equal(PCR(1000), clock.Update(makePcrPacket(100, 1000)))
+}
+
+func TestPCRClock_Push(t *testing.T) {
+	assert := assert.New(t)
+
+	clock := NewPCRClock(100)
+
+	// PCR interval is 40ms, bitrate changes on each interval
+	const interval = 40 * ProgramClock / 1000
+	counts := []int{30, 50, 20, 7, 64}
+
+	var expected []PCR
+	var result []PCR
+	onPacket := func(packet TS, pcr PCR) {
+		result = append(result, pcr)
+	}
+
+	data := NewTS(200)
+	clock.Push(data, onPacket)
+	expected = append(expected, NonPcr)
+
+	var base PCR = 1000
+	for _, count := range counts {
+		clock.Push(makePcrPacket(100, base), onPacket)
+		expected = append(expected, base)
+
+		for i := 1; i < count; i++ {
+			clock.Push(data, onPacket)
+			expected = append(expected, base.Add(PCR(interval*i/count)))
+		}
+
+		base = base.Add(interval)
+	}
+
+	clock.Push(makePcrPacket(100, base), onPacket)
+	expected = append(expected, base)
+
+	if !assert.Equal(len(expected), len(result)) {
+		return
+	}
+
+	for i := range expected {
+		if expected[i] == NonPcr {
+			assert.Equal(NonPcr, result[i], "packet #%d", i)
+			continue
+		}
+
+		jitter := result[i].Jitter(expected[i])
+		if jitter > time.Second {
+			jitter = expected[i].Jitter(result[i])
+		}
+		assert.LessOrEqual(jitter, 500*time.Nanosecond, "packet #%d", i)
+	}
+
+	// extrapolated values on flush
+	result = result[:0]
+	clock.Push(data, onPacket)
+	assert.Empty(result)
+	clock.Flush(onPacket)
+	assert.Equal([]PCR{base.Add(interval / 64)}, result)
+}
+
+func TestPCRRestamper_Restamp(t *testing.T) {
+	assert := assert.New(t)
+
+	const bitrate = 10000000
+	restamper, err := NewPCRRestamper(bitrate)
+	assert.NoError(err)
+	data := NewTS(200)
+
+	assert.Equal(NonPcr, restamper.PCR())
+
+	first := makePcrPacket(100, 1000000)
+	restamper.Restamp(first)
+	assert.Equal(PCR(1000000), first.PCR())
+
+	// source PCR values are invalid after packets removing
+	var previous PCR = first.PCR()
+	for n := 0; n < 1000; n++ {
+		for i := 0; i < 37; i++ {
+			restamper.Restamp(data)
+		}
+
+		packet := makePcrPacket(100, 0)
+		restamper.Restamp(packet)
+
+		expected := time.Duration(38*PacketSize*8) * time.Second / bitrate
+		jitter := packet.PCR().Jitter(previous)
+		assert.InDelta(int64(expected), int64(jitter), 500)
+
+		previous = packet.PCR()
+	}
+
+	// total drift
+	total := time.Duration(1000*38*PacketSize*8) * time.Second / bitrate
+	assert.InDelta(int64(total), int64(previous.Jitter(first.PCR())), 500)
+
+	// new base on discontinuity
+	packet := makePcrPacket(100, 42)
+	packet.SetDiscontinuity(true)
+	restamper.Restamp(packet)
+	assert.Equal(PCR(42), packet.PCR())
+}
+
+func TestPCRRestamper_SetBitrate(t *testing.T) {
+	assert := assert.New(t)
+
+	restamper, err := NewPCRRestamper(PacketSize * 8)
+	assert.NoError(err)
+	data := NewTS(200)
+
+	restamper.Restamp(makePcrPacket(100, 0))
+	restamper.Restamp(data)
+	assert.Equal(PCR(2*ProgramClock), restamper.PCR())
+
+	assert.ErrorIs(restamper.SetBitrate(0), ErrPcrBitrate)
+	assert.NoError(restamper.SetBitrate(PacketSize * 8 * 2))
+	restamper.Restamp(data)
+
+	packet := makePcrPacket(100, 0)
+	restamper.Restamp(packet)
+	assert.Equal(PCR(2.5*ProgramClock), packet.PCR())
+}
+
+func TestPCRRestamper_InvalidBitrate(t *testing.T) {
+	assert := assert.New(t)
+
+	restamper, err := NewPCRRestamper(0)
+	assert.Nil(restamper)
+	assert.ErrorIs(err, ErrPcrBitrate)
+
+	_, err = NewPCRRestamper(-1)
+	assert.ErrorIs(err, ErrPcrBitrate)
+}
@@ -2,45 +2,343 @@ package mpegts
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 )
 
+const (
+	// M2TS packet (Blu-ray, AVCHD) with 4 bytes prefix:
+	// 2-bit copy_permission_indicator and 30-bit arrival_time_stamp
+	M2TSPacketSize int = 192
+
+	// DVB-ASI packet with 16 bytes Reed-Solomon parity suffix
+	RSPacketSize int = 204
+
+	// Number of sequential sync bytes required to detect packet size
+	slicerDetectCount = 3
+
+	// Maximum bytes to keep while packet size detection
+	slicerDetectLimit = (slicerDetectCount + 1) * RSPacketSize * 4
+)
+
 // Slicer is a tool for split TS buffer to TS packets.
 // If buffer length is not multiple to TS packet size it store remain
 // data in the internal buffer and will be used on the next iteration.
+//
+// Zero value Slicer splits 188-byte packets.
+// Use NewSlicer to split M2TS (192-byte) or DVB-ASI (204-byte) packets,
+// or to detect packet size automatically.
 type Slicer struct {
-	packet [188]byte
+	packet [RSPacketSize]byte
 	fill   int
 
 	buffer []byte
 	skip   int
 
 	err error
+
+	size  int    // packet size in the stream. 0 is equal to PacketSize
+	auto  bool   // packet size is not detected yet
+	probe []byte // data collected to detect packet size or regain sync
+	extra []byte // prefix or suffix of the current packet
+
+	resync int    // number of packets to regain sync. 0 if resync disabled
+	synced bool   // stream position verified with resync packets
+	lost   bool   // sync lost and not regained yet
+	drop   int    // bytes dropped since sync loss
+	syncFn SyncFn // sync state callback
+	stats  SlicerStats
+}
+
+// SyncEvent is a Slicer synchronization state change
+type SyncEvent int
+
+const (
+	SyncLost SyncEvent = iota
+	SyncRegained
+)
+
+// SyncFn is a Slicer callback on synchronization state change.
+// dropped is a number of bytes skipped between sync loss and regain.
+// For SyncLost event dropped is always 0.
+type SyncFn func(event SyncEvent, dropped int)
+
+// SlicerStats contains Slicer synchronization counters
+type SlicerStats struct {
+	SyncLoss     int // number of sync loss events
+	SyncRegained int // number of sync regain events
+	Dropped      int // total number of bytes dropped to regain sync
 }
 
 var (
 	ErrSyncTS      = errors.New("ts slicer: sync error")
 	ErrNotComplete = errors.New("ts slicer: not complete")
+	ErrPacketSize  = errors.New("ts slicer: unsupported packet size")
 )
 
+// NewSlicer returns a new Slicer for packets with defined size:
+// PacketSize, M2TSPacketSize, or RSPacketSize.
+// If size is 0 packet size will be detected by the sync byte periodicity.
+// Returns ErrPacketSize for other sizes.
+func NewSlicer(size int) (*Slicer, error) {
+	s := new(Slicer)
+
+	switch size {
+	case 0:
+		s.auto = true
+	case PacketSize, M2TSPacketSize, RSPacketSize:
+		s.size = size
+	default:
+		return nil, ErrPacketSize
+	}
+
+	return s, nil
+}
+
+// Size returns size of the packet in the stream including prefix or suffix.
+// Returns 0 if packet size is not detected yet.
+func (s *Slicer) Size() int {
+	if s.auto {
+		return 0
+	}
+
+	if s.size == 0 {
+		return PacketSize
+	}
+
+	return s.size
+}
+
+// Extra returns bytes of the current packet that are not part of TS:
+// 4 bytes prefix for M2TS packet, 16 bytes Reed-Solomon parity for 204-byte packet.
+// Returns nil for 188-byte packets.
+func (s *Slicer) Extra() []byte {
+	return s.extra
+}
+
+// ArrivalTimestamp returns 30-bit arrival_time_stamp of the current M2TS packet.
+// Arrival Time Stamp is a 27MHz clock value.
+// Returns 0 for other packet sizes.
+func (s *Slicer) ArrivalTimestamp() uint32 {
+	if len(s.extra) != 4 || s.Size() != M2TSPacketSize {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(s.extra) & 0x3FFFFFFF
+}
+
+// syncOffset returns position of the sync byte in the stream packet
+func syncOffset(size int) int {
+	if size == M2TSPacketSize {
+		return M2TSPacketSize - PacketSize
+	}
+
+	return 0
+}
+
+// detectPacketSize looks for sync byte repeated with period of the
+// supported packet sizes.
+// Returns packet size and position of the first packet.
+// Returns 0 if buffer is not enough to detect packet size.
+func detectPacketSize(b []byte) (int, int) {
+	sizes := [...]int{PacketSize, M2TSPacketSize, RSPacketSize}
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != SyncByte {
+			continue
+		}
+
+		if i+(slicerDetectCount*RSPacketSize) >= len(b) {
+			break
+		}
+
+		for _, size := range sizes {
+			n := 1
+			for n <= slicerDetectCount && b[i+(n*size)] == SyncByte {
+				n += 1
+			}
+
+			if n > slicerDetectCount {
+				skip := i - syncOffset(size)
+				if skip < 0 {
+					skip += size
+				}
+				return size, skip
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+// findSync looks for count sequential packets with sync byte.
+// Returns position of the first packet or -1 if buffer is not enough.
+func findSync(b []byte, size, count int) int {
+	offset := syncOffset(size)
+
+	for i := 0; i+((count-1)*size)+offset < len(b); i++ {
+		n := 0
+		for n < count && b[i+(n*size)+offset] == SyncByte {
+			n += 1
+		}
+
+		if n == count {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// SetResync enables sync byte validation for each packet.
+// On sync loss Slicer drops data until count sequential packets
+// with sync byte found. ISO/IEC TR 101 290 recommends 5 packets.
+// Err returns ErrSyncTS if data is dropped before the first sync
+// or after sync loss, as on packet size detection.
+// fn is called on sync loss and regain, could be nil.
+func (s *Slicer) SetResync(count int, fn SyncFn) {
+	if count < 1 {
+		count = 1
+	}
+
+	s.resync = count
+	s.syncFn = fn
+}
+
+// Stats returns synchronization counters
+func (s *Slicer) Stats() SlicerStats {
+	return s.stats
+}
+
+func (s *Slicer) loseSync() {
+	s.synced = false
+	if s.lost {
+		return
+	}
+
+	s.lost = true
+	s.drop = 0
+	s.stats.SyncLoss += 1
+
+	if s.syncFn != nil {
+		s.syncFn(SyncLost, 0)
+	}
+}
+
+// search collects data to detect packet size or to regain sync.
+// Returns false if more data required.
+func (s *Slicer) search(buffer []byte) bool {
+	s.probe = append(s.probe, buffer...)
+
+	size := s.size
+	skip := -1
+
+	if s.auto {
+		size, skip = detectPacketSize(s.probe)
+		if size == 0 {
+			skip = -1
+		}
+	} else {
+		skip = findSync(s.probe, s.Size(), s.resync)
+	}
+
+	if skip == -1 {
+		limit := slicerDetectLimit
+		tail := slicerDetectCount * RSPacketSize
+		if !s.auto {
+			limit = (s.resync + 1) * s.Size() * 4
+			tail = s.resync * s.Size()
+		}
+
+		if len(s.probe) > limit {
+			// keep tail with possible packets start
+			n := len(s.probe) - tail
+			s.probe = append(s.probe[:0], s.probe[n:]...)
+			s.dropBytes(n)
+			s.err = ErrSyncTS
+		}
+
+		if s.lost {
+			s.err = ErrSyncTS
+		}
+
+		return false
+	}
+
+	if skip > 0 {
+		s.dropBytes(skip)
+		s.err = ErrSyncTS
+	}
+
+	s.auto = false
+	s.size = size
+	s.synced = true
+	s.buffer = s.probe
+	s.skip = skip
+	s.probe = nil
+
+	if s.lost {
+		s.lost = false
+		s.stats.SyncRegained += 1
+
+		if s.syncFn != nil {
+			s.syncFn(SyncRegained, s.drop)
+		}
+	}
+
+	return true
+}
+
+func (s *Slicer) dropBytes(n int) {
+	s.drop += n
+	s.stats.Dropped += n
+}
+
 // Prepares buffer and get first packet
 func (s *Slicer) Begin(buffer []byte) TS {
 	s.buffer = buffer
 	s.skip = 0
 	s.err = nil
+	s.extra = nil
+
+	if s.auto || (s.resync != 0 && !s.synced) {
+		if !s.search(buffer) {
+			s.buffer = buffer
+			s.skip = len(s.buffer)
+			return nil
+		}
+
+		return s.Next()
+	}
+
+	size := s.Size()
 
 	// some data remain in the buffer after previous iteration
 	if s.fill != 0 {
-		size := copy(s.packet[s.fill:], s.buffer)
-		s.fill += size
-		s.skip += size
+		n := copy(s.packet[s.fill:size], s.buffer)
+		s.fill += n
+		s.skip += n
 
-		if s.fill != PacketSize {
+		if s.fill != size {
 			return nil
 		}
 
 		s.fill = 0
-		return TS(s.packet[:])
+
+		if s.resync != 0 && s.packet[syncOffset(size)] != SyncByte {
+			s.loseSync()
+			s.dropBytes(1)
+			s.probe = append(s.probe[:0], s.packet[1:size]...)
+			if !s.search(buffer[n:]) {
+				s.buffer = buffer
+				s.skip = len(s.buffer)
+				return nil
+			}
+
+			return s.Next()
+		}
+
+		return s.slice(s.packet[:size])
 	}
 
 	if len(s.buffer) == 0 {
@@ -48,7 +346,15 @@ func (s *Slicer) Begin(buffer []byte) TS {
 	}
 
 	// check TS sync byte
-	s.skip = bytes.IndexByte(s.buffer, SyncByte)
+	offset := syncOffset(size)
+	if len(s.buffer) > offset && s.buffer[offset] == SyncByte {
+		s.skip = 0
+	} else if len(s.buffer) > offset {
+		s.skip = bytes.IndexByte(s.buffer[offset:], SyncByte)
+	} else {
+		s.skip = -1
+	}
+
 	if s.skip == -1 {
 		s.err = ErrSyncTS
 		return nil
@@ -57,23 +363,67 @@ func (s *Slicer) Begin(buffer []byte) TS {
 	return s.Next()
 }
 
+// slice returns TS packet from the stream packet and keeps extra bytes
+func (s *Slicer) slice(b []byte) TS {
+	switch len(b) {
+	case M2TSPacketSize:
+		s.extra = b[:M2TSPacketSize-PacketSize]
+		return TS(b[M2TSPacketSize-PacketSize:])
+	case RSPacketSize:
+		s.extra = b[PacketSize:]
+		return TS(b[:PacketSize])
+	default:
+		s.extra = nil
+		return TS(b)
+	}
+}
+
 // Get next packet
 func (s *Slicer) Next() TS {
-	next := s.skip + PacketSize
+	size := s.Size()
+	next := s.skip + size
 	if len(s.buffer) >= next {
+		if s.resync != 0 && s.buffer[s.skip+syncOffset(size)] != SyncByte {
+			return s.nextResync()
+		}
+
 		p := s.buffer[s.skip:next]
 		s.skip = next
-		return TS(p)
+		return s.slice(p)
 	}
 
 	if len(s.buffer) > s.skip {
+		offset := syncOffset(size)
+		if s.resync != 0 && len(s.buffer) > s.skip+offset && s.buffer[s.skip+offset] != SyncByte {
+			return s.nextResync()
+		}
+
 		s.fill = copy(s.packet[:], s.buffer[s.skip:])
 		s.skip += s.fill
 	}
 
+	s.extra = nil
 	return nil
 }
 
+// nextResync drops current position and looks for the next packet with sync byte
+func (s *Slicer) nextResync() TS {
+	s.loseSync()
+	s.dropBytes(1)
+
+	buffer := s.buffer
+	remain := buffer[s.skip+1:]
+
+	if !s.search(remain) {
+		s.buffer = buffer
+		s.skip = len(s.buffer)
+		s.extra = nil
+		return nil
+	}
+
+	return s.Next()
+}
+
 // Returns number of bytes processed and error if happens
 func (s *Slicer) Err() error {
 	if s.err != nil {
@@ -185,3 +185,201 @@ func TestSlicer(t *testing.T) {
 		assert.NoError(err)
 	})
 }
+
+func makeSlicerStream(size, count int) []byte {
+	offset := syncOffset(size)
+	stream := make([]byte, size*count)
+
+	for i := 0; i < count; i++ {
+		unit := stream[i*size : (i+1)*size]
+		packet := unit[offset : offset+PacketSize]
+		copy(packet, NullTS)
+		packet[3] = 0x10 | byte(i&0x0F)
+
+		switch size {
+		case M2TSPacketSize:
+			// copy_permission_indicator and arrival_time_stamp
+			unit[0] = 0xC0
+			unit[3] = byte(i)
+		case RSPacketSize:
+			for z := PacketSize; z < size; z++ {
+				unit[z] = 0xA0 + byte(z-PacketSize)
+			}
+		}
+	}
+
+	return stream
+}
+
+func TestSlicer_Size(t *testing.T) {
+	for _, size := range []int{PacketSize, M2TSPacketSize, RSPacketSize} {
+		stream := makeSlicerStream(size, 10)
+
+		t.Run(fmt.Sprintf("fixed %d", size), func(t *testing.T) {
+			assert := assert.New(t)
+
+			slicer, err := NewSlicer(size)
+			assert.NoError(err)
+			count := 0
+			for packet := slicer.Begin(stream); packet != nil; packet = slicer.Next() {
+				assert.Equal(PacketSize, len(packet))
+				assert.Equal(uint8(count), packet.CC())
+				assert.Equal(size-PacketSize, len(slicer.Extra()))
+				if size == M2TSPacketSize {
+					assert.Equal(uint32(count), slicer.ArrivalTimestamp())
+				}
+				count += 1
+			}
+			assert.Equal(10, count)
+			assert.NoError(slicer.Err())
+		})
+
+		t.Run(fmt.Sprintf("detect %d", size), func(t *testing.T) {
+			assert := assert.New(t)
+
+			slicer, err := NewSlicer(0)
+			assert.NoError(err)
+			count := 0
+
+			// unaligned begin and small buffers
+			for skip := 7; skip < len(stream); skip += 100 {
+				end := skip + 100
+				if end > len(stream) {
+					end = len(stream)
+				}
+
+				for packet := slicer.Begin(stream[skip:end]); packet != nil; packet = slicer.Next() {
+					assert.Equal(uint8(count+1), packet.CC())
+					if size == RSPacketSize {
+						assert.Equal(byte(0xA0), slicer.Extra()[0])
+					}
+					count += 1
+				}
+			}
+
+			assert.Equal(size, slicer.Size())
+			assert.Equal(9, count)
+			assert.NoError(slicer.Err())
+		})
+	}
+}
+
+func TestSlicer_DetectError(t *testing.T) {
+	assert := assert.New(t)
+
+	slicer, err := NewSlicer(0)
+	assert.NoError(err)
+	buffer := make([]byte, slicerDetectLimit+1)
+
+	assert.Nil(slicer.Begin(buffer))
+	assert.ErrorIs(slicer.Err(), ErrSyncTS)
+	assert.Equal(0, slicer.Size())
+
+	_, err = NewSlicer(200)
+	assert.ErrorIs(err, ErrPacketSize)
+
+	// garbage before the first sync with fixed packet size
+	slicer, err = NewSlicer(PacketSize)
+	assert.NoError(err)
+	slicer.SetResync(3, nil)
+
+	assert.Nil(slicer.Begin(buffer))
+	assert.ErrorIs(slicer.Err(), ErrSyncTS)
+	assert.NotZero(slicer.Stats().Dropped)
+
+	// few garbage bytes before the first sync
+	stream := makeSlicerStream(PacketSize, 4)
+	garbage := append([]byte{0x00, 0x01, 0x02}, stream...)
+
+	slicer, err = NewSlicer(PacketSize)
+	assert.NoError(err)
+	slicer.SetResync(3, nil)
+
+	count := 0
+	for packet := slicer.Begin(garbage); packet != nil; packet = slicer.Next() {
+		count += 1
+	}
+	assert.Equal(4, count)
+	assert.ErrorIs(slicer.Err(), ErrSyncTS)
+	assert.Equal(3, slicer.Stats().Dropped)
+
+	// next buffer without garbage
+	count = 0
+	for packet := slicer.Begin(stream); packet != nil; packet = slicer.Next() {
+		count += 1
+	}
+	assert.Equal(4, count)
+	assert.NoError(slicer.Err())
+}
+
+func TestSlicer_Resync(t *testing.T) {
+	stream := makeSlicerStream(PacketSize, 10)
+
+	// insert garbage after 4 packets
+	garbage := []byte{0x00, 0x47, 0x01, 0x02, 0x47, 0x03, 0x04, 0x05, 0x06, 0x07}
+	broken := make([]byte, 0, len(stream)+len(garbage))
+	broken = append(broken, stream[:4*PacketSize]...)
+	broken = append(broken, garbage...)
+	broken = append(broken, stream[4*PacketSize:]...)
+
+	for _, chunk := range []int{len(broken), 1316, 100, 7} {
+		t.Run(fmt.Sprintf("chunk %d", chunk), func(t *testing.T) {
+			assert := assert.New(t)
+
+			var events []SyncEvent
+			dropped := 0
+
+			slicer, err := NewSlicer(PacketSize)
+			assert.NoError(err)
+			slicer.SetResync(3, func(event SyncEvent, n int) {
+				events = append(events, event)
+				dropped += n
+			})
+
+			var cc []uint8
+			for skip := 0; skip < len(broken); skip += chunk {
+				end := skip + chunk
+				if end > len(broken) {
+					end = len(broken)
+				}
+
+				for packet := slicer.Begin(broken[skip:end]); packet != nil; packet = slicer.Next() {
+					assert.Equal(SyncByte, packet[0])
+					cc = append(cc, packet.CC())
+				}
+			}
+
+			assert.Equal([]uint8{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, cc)
+			assert.Equal([]SyncEvent{SyncLost, SyncRegained}, events)
+			assert.Equal(len(garbage), dropped)
+
+			stats := slicer.Stats()
+			assert.Equal(1, stats.SyncLoss)
+			assert.Equal(1, stats.SyncRegained)
+			assert.Equal(len(garbage), stats.Dropped)
+		})
+	}
+}
+
+func TestSlicer_ResyncLost(t *testing.T) {
+	assert := assert.New(t)
+
+	stream := makeSlicerStream(PacketSize, 6)
+	for i := PacketSize * 3; i < len(stream); i++ {
+		stream[i] = 0x00
+	}
+
+	slicer, err := NewSlicer(PacketSize)
+	assert.NoError(err)
+	slicer.SetResync(3, nil)
+
+	count := 0
+	for packet := slicer.Begin(stream); packet != nil; packet = slicer.Next() {
+		count += 1
+	}
+
+	assert.Equal(3, count)
+	assert.ErrorIs(slicer.Err(), ErrSyncTS)
+	assert.Equal(1, slicer.Stats().SyncLoss)
+	assert.Equal(0, slicer.Stats().SyncRegained)
+}